@@ -60,7 +60,10 @@ func App(inputFile string, outputDirectory string, debug bool) *errors.StackErro
     }
 
     middleend := me.New(frontend.Result)
-    middleend.Work()
+    err3 := middleend.Work()
+    if err3 != nil {
+        return err3
+    }
 
     typeString, exportString := middleend.GetResults()
 
@@ -77,7 +80,7 @@ func App(inputFile string, outputDirectory string, debug bool) *errors.StackErro
     
     fmt.Println("")
     ui.Log(config.GRANDMASTER, "info", "Done compiling! Took " + fmt.Sprintf("%s", totalTime))
-    ui.Log(config.GRANDMASTER, "info", "Output is at '"+filepath.Join(outputDirectory, frontend.Result.Exports + ".luau")+"'")
+    ui.Log(config.GRANDMASTER, "info", "Output is at '"+filepath.Join(outputDirectory, frontend.Result.Name + ".luau")+"'")
 
     fmt.Println("")
 
@@ -0,0 +1,139 @@
+package backend
+
+import (
+    "strings"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/language"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+func usesAnyType(scheme *types.Scheme) bool {
+    uses := false
+
+    walkSchemeTypes(scheme, func(_type *types.Type) {
+        if _type.Name == "any" {
+            uses = true
+        }
+    })
+
+    return uses
+}
+
+func getAnyTagsTable() string {
+    tags := make([]string, 0, len(language.AnyTypeTags))
+
+    for i, kind := range language.AnyTypeTags {
+        tags = append(tags, format("[\"%s\"] = %d", kind, i))
+    }
+
+    return format("local anyTags = { %s }\n", strings.Join(tags, ", "))
+}
+
+// Tables can reference themselves, so writes are depth limited by the same limit as reads.
+func getWriteFunctionForAny() []string {
+    out := []string{
+        "function write_any(cursor : number, input : any) : number",
+        "    local kind = typeof(input)",
+        "    local tag = anyTags[kind]",
+        "    if tag == nil then",
+        "        error(\"Values of type '\" .. kind .. \"' can't be sent as any.\")",
+        "    end",
+        "    cursor = writer.write_u8(sharedBuffer, cursor, tag)",
+    }
+
+    for _, kind := range language.AnyTypeTags {
+        if wireType, hasValue := language.AnyTypeWireTypes[kind]; hasValue {
+            out = append(out, format("    if kind == \"%s\" then", kind))
+            out = append(out, format("        return writer.write_%s(sharedBuffer, cursor, input)", wireType))
+            out = append(out, "    end")
+        }
+    }
+
+    return append(out,
+        "    if kind == \"table\" then",
+        "        writeDepth += 1",
+        "        if writeDepth > maxReadDepth then",
+        "            error(\"Any table is nested deeper than \" .. maxReadDepth .. \" levels, it may reference itself.\")",
+        "        end",
+        "        local count = 0",
+        "        for _ in input do",
+        "            count += 1",
+        "        end",
+        "        cursor = writer.write_vu32(sharedBuffer, cursor, count)",
+        "        for key, value in input do",
+        "            cursor = write_any(cursor, key)",
+        "            cursor = write_any(cursor, value)",
+        "        end",
+        "        writeDepth -= 1",
+        "    end",
+        "    return cursor",
+        "end\n",
+    )
+}
+
+// Tables nest, so they are depth limited like recursive structs.
+func getReadFunctionForAny() []string {
+    out := []string{
+        "function read_any(buff : buffer, cursor : number) : (number, any)",
+        "    local tag",
+        "    cursor, tag = reader.read_u8(buff, cursor)",
+        "    local kind = anyKinds[tag + 1]",
+    }
+
+    for _, kind := range language.AnyTypeTags {
+        if wireType, hasValue := language.AnyTypeWireTypes[kind]; hasValue {
+            out = append(out, format("    if kind == \"%s\" then", kind))
+            out = append(out, format("        return reader.read_%s(buff, cursor)", wireType))
+            out = append(out, "    end")
+        }
+    }
+
+    out = append(out,
+        "    if kind == \"nil\" then",
+        "        return cursor, nil",
+        "    elseif kind ~= \"table\" then",
+        "        error(\"Unknown any tag '\" .. tag .. \"'.\")",
+        "    end",
+    )
+
+    out = append(out, getReadStringForDepthEnter()...)
+
+    return append(out,
+        "    local count, key, value",
+        "    local output = {}",
+        "    cursor, count = reader.read_vu32(buff, cursor)",
+        "    for _ = 1, count do",
+        "        cursor, key = read_any(buff, cursor)",
+        "        cursor, value = read_any(buff, cursor)",
+        "        if key ~= nil then",
+        "            output[key] = value",
+        "        end",
+        "    end",
+        getReadStringForDepthExit(),
+        "    return cursor, output",
+        "end\n",
+    )
+}
+
+// Public Functions
+func AnyToFunctions(scheme *types.Scheme) []string {
+    if !usesAnyType(scheme) {
+        return []string{}
+    }
+
+    kinds := make([]string, 0, len(language.AnyTypeTags))
+    for _, kind := range language.AnyTypeTags {
+        kinds = append(kinds, format("\"%s\"", kind))
+    }
+
+    out := []string{
+        format("local anyKinds = { %s }", strings.Join(kinds, ", ")),
+        "local writeDepth = 0",
+        getAnyTagsTable(),
+    }
+
+    out = append(out, getWriteFunctionForAny()...)
+
+    return append(out, getReadFunctionForAny()...)
+}
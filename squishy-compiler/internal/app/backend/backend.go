@@ -47,7 +47,8 @@ var template string
     @privatevariables
     *   @privatevariable scheme : *types.Scheme ;; Pointer to scheme created by frontend.
     @privatemethods
-    *   @privatemethod
+    *   @privatemethod getExportBodies
+    *   @privatemethod getLibFunctions
     @publicmethods
     *   @publicmethod Work
     *   @publicmethod Debug
@@ -90,6 +91,60 @@ func NewWithoutPath(scheme *types.Scheme,
     }
 }
 
+// Private Methods
+func (backend *Backend) getExportBodies(name string) ([]string, []string, string) {
+    if exportUnion, isUnion := backend.scheme.Unions[name]; isUnion {
+        readBody, readReturn := UnionToReadString(exportUnion)
+        return UnionToWriteString(exportUnion), readBody, readReturn
+    }
+
+    exportStruct := backend.scheme.Structs[name]
+    readBody, readReturn := StructToReadString(exportStruct, backend.scheme.Structs, backend.scheme.Unions, backend.scheme.Enums)
+
+    return StructToWriteString(exportStruct, backend.scheme.Enums), readBody, readReturn
+}
+
+// Fills write, read and new functions of template for given export, path is 'scheme' or 'scheme.<Export>'.
+func (backend *Backend) getLibFunctions(lines []string, name string, path string) []string {
+    exportFunctionWriteBody, exportFunctionReadBody, exportFunctionReadReturn := backend.getExportBodies(name)
+
+    out := []string{}
+
+    out = append(out, format("function %s.write(input : %s) : (buffer?, {Instance})", path, name))
+    out = append(out, lines[42:44]...)
+
+    // Depth is reset since a write erroring midway leaves it raised.
+    if usesAnyType(backend.scheme) {
+        out = append(out, "    writeDepth = 0")
+    }
+    out = append(out, " ")
+    out = append(out, "    "+strings.Join(exportFunctionWriteBody, "\n    "))
+    out = append(out, " ")
+    out = append(out, lines[45:50]...)
+    out = append(out, format("function %s.read(buff : buffer, instances : {Instance}?) : %s?", path, name))
+    out = append(out, lines[51:53]...)
+
+    // Depth is reset since a read erroring midway leaves it raised.
+    if hasRecursion(backend.scheme) {
+        out = append(out, "    readDepth = 0")
+    }
+    out = append(out, " ")
+    out = append(out, "    " + strings.Join(exportFunctionReadBody, "\n    "))
+    out = append(out, " ")
+    out = append(out, "    return " + strings.Join(strings.Split(exportFunctionReadReturn, ";"), ";\n            "))
+    out = append(out, lines[55])
+
+    // Unions have no single shape to construct.
+    if exportStruct, isStruct := backend.scheme.Structs[name]; isStruct {
+        out = append(out, lines[56])
+        out = append(out, format("function %s.new() : %s", path, name))
+        out = append(out, "    return "+StructToNewString(exportStruct, backend.scheme.Structs, backend.scheme.Unions, backend.scheme.Enums, "    "))
+        out = append(out, lines[55])
+    }
+
+    return out
+}
+
 // Public Methods
 func (backend *Backend) GetString() string {
     var lines []string
@@ -101,38 +156,69 @@ func (backend *Backend) GetString() string {
 
     now := time.Now()
 
-    writeFunctions := StructListToWriteFunctions(backend.sortedStructs, backend.scheme.Structs)
-    readFunctions := StructListToReadFunctions(backend.sortedStructs, backend.scheme.Structs)
-    exportFunctionWriteBody := StructToWriteString(backend.scheme.Structs[backend.scheme.Exports])
-    exportFunctionReadBody, exportFunctionReadReturn := StructToReadString(backend.scheme.Structs[backend.scheme.Exports])
+    enumFunctions := EnumListToFunctions(backend.sortedStructs, backend.scheme.Enums)
+    robloxEnumFunctions := RobloxEnumsToFunctions(backend.scheme)
+    anyFunctions := AnyToFunctions(backend.scheme)
+    writeFunctions := StructListToWriteFunctions(backend.sortedStructs, backend.scheme.Structs, backend.scheme.Enums)
+    readFunctions := StructListToReadFunctions(backend.sortedStructs, backend.scheme.Structs, backend.scheme.Unions, backend.scheme.Enums)
+    unionFunctions := UnionListToFunctions(backend.sortedStructs, backend.scheme.Unions)
+    hasMultipleExports := len(backend.scheme.Exports) > 1
 
-    lines[7] = format("    * @file     : %s%s%s%s", backend.outputPath, "/", backend.scheme.Exports, ".luau")
+    lines[7] = format("    * @file     : %s%s%s%s", backend.outputPath, "/", backend.scheme.Name, ".luau")
     lines[8] = "    * @author   : squishy-compiler"
     lines[9] = format("    * @date     : %s", now.Format("January 2 2006"))
     lines[10] = format("    * @lastEdit : %s @ %s", now.Format("January 2 2006"), now.Format("15:04"))
-    lines[11] = format("    * @brief    : Squishy IDL Compiler generated code for %s.", backend.scheme.Exports)
-    lines[40] = format("function scheme.write(input : %s) : buffer?", backend.scheme.Exports)
-    lines[48] = format("function scheme.read(buff : buffer) : %s?", backend.scheme.Exports)
-    lines[51] = "    return " + strings.Join(strings.Split(exportFunctionReadReturn, ";"), ";\n            ")
+    lines[11] = format("    * @brief    : Squishy IDL Compiler generated code for %s.", strings.Join(backend.scheme.Exports, ", "))
 
     out := []string{}
 
     out = append(out, lines[0:28]...)
     out = append(out, backend.typeString)
-    out = append(out, lines[29:33]...)
+    out = append(out, lines[29:32]...)
+
+    if hasRecursion(backend.scheme) {
+        out = append(out, getRecursionVariables()...)
+    }
+
+    out = append(out, lines[32:34]...)
+    out = append(out, RangeToFunctions(backend.scheme)...)
+    out = append(out, enumFunctions...)
+    out = append(out, robloxEnumFunctions...)
+    out = append(out, anyFunctions...)
     out = append(out, writeFunctions...)
     out = append(out, readFunctions...)
-    out = append(out, lines[34:38]...)
+    out = append(out, unionFunctions...)
+    out = append(out, lines[35:37]...)
+    out = append(out, ConstantsToString(backend.scheme.Constants)...)
+
+    if hasMultipleExports {
+        for _, name := range backend.scheme.Exports {
+            out = append(out, format("scheme.%s = {}", name))
+        }
+    }
+
+    out = append(out, lines[37:39]...)
     out = append(out, backend.exportString)
-    out = append(out, lines[39:42]...)
-    out = append(out, " ")
-    out = append(out, "    "+strings.Join(exportFunctionWriteBody, "\n    "))
-    out = append(out, " ")
-    out = append(out, lines[43:50]...)
-    out = append(out, " ")
-    out = append(out, "    " + strings.Join(exportFunctionReadBody, "\n    "))
-    out = append(out, " ")
-    out = append(out, lines[51:]...)
+    out = append(out, lines[40])
+
+    for i, name := range backend.scheme.Exports {
+        if i > 0 {
+            out = append(out, lines[56])
+        }
+
+        if hasMultipleExports {
+            out = append(out, backend.getLibFunctions(lines, name, "scheme."+name)...)
+        } else {
+            out = append(out, backend.getLibFunctions(lines, name, "scheme")...)
+        }
+    }
+
+    if hasRecursion(backend.scheme) {
+        out = append(out, lines[56])
+        out = append(out, getSetMaxDepthFunction()...)
+    }
+
+    out = append(out, lines[56:]...)
 
     finalOutput := strings.Join(out, "\n")
 
@@ -142,7 +228,7 @@ func (backend *Backend) GetString() string {
 func (backend *Backend) Work() *errors.StackError {
     finalOutput := backend.GetString()
 
-    if err := file.CreateAndWriteFile(backend.outputPath, backend.scheme.Exports+".luau", finalOutput); err != nil {
+    if err := file.CreateAndWriteFile(backend.outputPath, backend.scheme.Name+".luau", finalOutput); err != nil {
         return err
     }
 
@@ -150,21 +236,28 @@ func (backend *Backend) Work() *errors.StackError {
 }
 
 func (backend *Backend) Debug() {
-    writeFunctions := StructListToWriteFunctions(backend.sortedStructs, backend.scheme.Structs)
-    exportFunctionWriteBody := StructToWriteString(backend.scheme.Structs[backend.scheme.Exports])
-    exportFunctionReadBody, exportFunctionReadReturn := StructToReadString(backend.scheme.Structs[backend.scheme.Exports])
+    writeFunctions := StructListToWriteFunctions(backend.sortedStructs, backend.scheme.Structs, backend.scheme.Enums)
 
-    writeFunctions = writeFunctions[:len(writeFunctions)-1]
+    if len(writeFunctions) > 0 {
+        writeFunctions = writeFunctions[:len(writeFunctions)-1]
+    }
 
     ui.Log(config.MASTER, "info", "BACKEND DEBUG START")
     ui.Log(config.FELLOWCRAFT, "info", "Write Functions:")
     ui.Log(config.APPRENTICE, "info", strings.Join(writeFunctions, "\n"+strings.Repeat(" ", 13)))
     ui.Log(config.FELLOWCRAFT, "info", "Read Functions:")
-    ui.Log(config.FELLOWCRAFT, "info", "Write Body:")
-    ui.Log(config.APPRENTICE, "info", strings.Join(exportFunctionWriteBody, "\n"+strings.Repeat(" ", 13)))
-    ui.Log(config.FELLOWCRAFT, "info", "Read Body:")
-    ui.Log(config.APPRENTICE, "info", strings.Join(exportFunctionReadBody, "\n"+strings.Repeat(" ", 13)))
-    ui.Log(config.FELLOWCRAFT, "info", "Read Return:")
-    ui.Log(config.APPRENTICE, "info", exportFunctionReadReturn)
+
+    for _, name := range backend.scheme.Exports {
+        exportFunctionWriteBody, exportFunctionReadBody, exportFunctionReadReturn := backend.getExportBodies(name)
+
+        ui.Log(config.FELLOWCRAFT, "info", "Export: "+name)
+        ui.Log(config.FELLOWCRAFT, "info", "Write Body:")
+        ui.Log(config.APPRENTICE, "info", strings.Join(exportFunctionWriteBody, "\n"+strings.Repeat(" ", 13)))
+        ui.Log(config.FELLOWCRAFT, "info", "Read Body:")
+        ui.Log(config.APPRENTICE, "info", strings.Join(exportFunctionReadBody, "\n"+strings.Repeat(" ", 13)))
+        ui.Log(config.FELLOWCRAFT, "info", "Read Return:")
+        ui.Log(config.APPRENTICE, "info", exportFunctionReadReturn)
+    }
+
     ui.Log(config.MASTER, "info", "BACKEND DEBUG END")
 }
@@ -0,0 +1,32 @@
+package backend
+
+import (
+    "sort"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Public Functions
+func ConstantsToString(constants map[string]*types.Constant) []string {
+    if len(constants) == 0 {
+        return []string{}
+    }
+
+    names := make([]string, 0, len(constants))
+
+    for name := range constants {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+
+    out := []string{"scheme.constants = {"}
+
+    for _, name := range names {
+        out = append(out, format("    %s = %d;", name, constants[name].Value))
+    }
+
+    out = append(out, "}")
+
+    return out
+}
@@ -0,0 +1,79 @@
+package backend
+
+import (
+    "slices"
+    "strings"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Returns value given to a field missing from payload, its default if it has one.
+func getMissingValue(field *types.Field, enums map[string]*types.Enum) string {
+    if field.Default != "" {
+        return field.Default
+    }
+
+    return getZeroValue(field.Type, enums)
+}
+
+// Optional fields with a default get it when they are absent.
+func getReadStringForDefaults(fields []*types.Field) []string {
+    out := []string{}
+
+    for _, field := range fields {
+        if field.Type.IsOptional && field.Default != "" {
+            out = append(out, wrapInCondition(getLocalName(field)+" == nil", format("%s = %s", getLocalName(field), field.Default))...)
+        }
+    }
+
+    return out
+}
+
+// Returns value of a field in a freshly constructed table, empty string means field is left nil.
+func getNewValue(field *types.Field, structs map[string]*types.Struct, unions map[string]*types.Union, enums map[string]*types.Enum) string {
+    if value := getMissingValue(field, enums); value != "" || field.Type.IsOptional {
+        return value
+    }
+
+    if nested, isStruct := structs[field.Type.Name]; isStruct && field.Type.IsReferenceToAnotherStruct {
+        return StructToNewString(nested, structs, unions, enums, "")
+    }
+
+    // Unions start as their first arm.
+    if _union, isUnion := unions[field.Type.Name]; isUnion && field.Type.IsReferenceToAUnion {
+        arm := _union.Arms[0]
+
+        if value := getNewValue(arm, structs, unions, enums); value != "" {
+            return format("{ kind = \"%s\"; value = %s; }", arm.Name, value)
+        }
+
+        return format("{ kind = \"%s\"; }", arm.Name)
+    }
+
+    return ""
+}
+
+// Public Functions
+
+// Returns a table literal with every field of struct filled, fields are put on their own lines with given indent.
+func StructToNewString(_struct *types.Struct, structs map[string]*types.Struct, unions map[string]*types.Union, enums map[string]*types.Enum, indent string) string {
+    entries := []string{}
+
+    for _, field := range append(slices.Clone(_struct.Inherited), _struct.Fields...) {
+        if value := getNewValue(field, structs, unions, enums); value != "" {
+            entries = append(entries, format("%s = %s;", field.Name, value))
+        }
+    }
+
+    if len(entries) == 0 {
+        return "{}"
+    }
+
+    if indent == "" {
+        return "{ " + strings.Join(entries, " ") + " }"
+    }
+
+    return "{\n" + indent + "    " + strings.Join(entries, "\n"+indent+"    ") + "\n" + indent + "}"
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+    "strings"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+func getEnumWireType(_enum *types.Enum) string {
+    if len(_enum.Members) <= 256 {
+        return "u8"
+    }
+
+    return "u16"
+}
+
+func getEnumLookupTables(_enum *types.Enum) []string {
+    values := make([]string, 0, len(_enum.Members))
+    indexes := make([]string, 0, len(_enum.Members))
+
+    for i, member := range _enum.Members {
+        values = append(values, format("\"%s\"", member))
+        indexes = append(indexes, format("[\"%s\"] = %d", member, i))
+    }
+
+    return []string{
+        format("local enumValues_%s = { %s }", _enum.Name, strings.Join(values, ", ")),
+        format("local enumIndexes_%s = { %s }\n", _enum.Name, strings.Join(indexes, ", ")),
+    }
+}
+
+// Values which aren't members have no index, writing them errors like unknown union kinds.
+func getEnumGuard(name string, value string) string {
+    return format("if enumIndexes_%s[%s] == nil then error(\"Invalid %s value '\" .. tostring(%s) .. \"'.\") end", name, value, name, value)
+}
+
+// Public Functions
+func EnumListToFunctions(list []string, enums map[string]*types.Enum) []string {
+    out := []string{}
+
+    for _, name := range list {
+        _enum, isEnum := enums[name]
+        if !isEnum {
+            continue
+        }
+
+        wireType := getEnumWireType(_enum)
+
+        out = append(out, getEnumLookupTables(_enum)...)
+
+        out = append(out, format("function write_%s(cursor : number, input : %s) : number", name, name))
+        out = append(out, "    "+getEnumGuard(name, "input"))
+        out = append(out, format("    return writer.write_%s(sharedBuffer, cursor, enumIndexes_%s[input])", wireType, name))
+        out = append(out, "end\n")
+
+        out = append(out, format("function read_%s(buff : buffer, cursor : number) : (number, %s)", name, name))
+        out = append(out, "    local index")
+        out = append(out, format("    cursor, index = reader.read_%s(buff, cursor)", wireType))
+        out = append(out, format("    return cursor, enumValues_%s[index + 1]", name))
+        out = append(out, "end\n")
+    }
+
+    return out
+}
@@ -0,0 +1,109 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/language"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Structs with field ids are evolvable, every present field is sent as 'id, length, value' and fields end with id 0.
+func isEvolvable(_struct *types.Struct) bool {
+    return len(_struct.Fields) > 0 && _struct.Fields[0].ID != 0
+}
+
+// Returns value used when a field is missing from payload, empty string means there is none.
+func getZeroValue(_type *types.Type, enums map[string]*types.Enum) string {
+    if _type.IsOptional {
+        return ""
+    }
+
+    if _type.IsArray || _type.IsMap {
+        return "{}"
+    }
+
+    if _type.Range != nil {
+        return format("%d", _type.Range.Min)
+    }
+
+    // Zero can be outside of a quantization, its minimum always fits.
+    if q := _type.Quantization; q != nil {
+        minimum := formatNumber(q.Min)
+
+        switch _type.Name {
+        case "qvector3":
+            return format("Vector3.new(%s, %s, %s)", minimum, minimum, minimum)
+        case "qcframe":
+            return format("CFrame.new(%s, %s, %s)", minimum, minimum, minimum)
+        }
+
+        return minimum
+    }
+
+    if _type.IsReferenceToAnEnum {
+        return format("\"%s\"", enums[_type.Name].Members[0])
+    }
+
+    if _type.RobloxEnum != "" {
+        return format("Enum.%s:GetEnumItems()[1]", _type.RobloxEnum)
+    }
+
+    if robloxType, isDefault := language.DefaultTypesToRobloxTypes[_type.Name]; isDefault {
+        return language.RobloxTypeZeroValues[robloxType]
+    }
+
+    return ""
+}
+
+func evolvableStructToWriteString(_struct *types.Struct) []string {
+    out := []string{}
+
+    for _, field := range _struct.Fields {
+        line := format("cursor = writer.write_field(sharedBuffer, cursor, %d, input.%s, %s)", field.ID, field.Name, getWriteFunctionForType(field.Type))
+
+        if field.Type.IsOptional {
+            out = append(out, wrapInCondition(format("input.%s ~= nil", field.Name), line)...)
+            continue
+        }
+
+        out = append(out, line)
+    }
+
+    return append(out, "cursor = writer.write_vu32(sharedBuffer, cursor, 0) -- End of fields")
+}
+
+func evolvableStructToReadString(_struct *types.Struct, structs map[string]*types.Struct, unions map[string]*types.Union, enums map[string]*types.Enum) []string {
+    out := []string{
+        "local fieldId, fieldLength",
+        "while true do",
+        "    cursor, fieldId = reader.read_vu32(buff, cursor)",
+        "    if fieldId == 0 then",
+        "        break",
+        "    end",
+        "    cursor, fieldLength = reader.read_vu32(buff, cursor)",
+        "    local fieldEnd = cursor + fieldLength",
+    }
+
+    for i, field := range _struct.Fields {
+        keyword := "elseif"
+        if i == 0 {
+            keyword = "if"
+        }
+
+        out = append(out, format("    %s fieldId == %d then", keyword, field.ID))
+        out = append(out, "        "+getReadStringForField(field))
+    }
+
+    out = append(out, "    end")
+    out = append(out, "    cursor = fieldEnd -- Unknown fields are skipped")
+    out = append(out, "end")
+
+    // Missing nested structs are constructed like scheme.new does, so every non optional field ends up set.
+    for _, field := range _struct.Fields {
+        if missingValue := getNewValue(field, structs, unions, enums); missingValue != "" {
+            out = append(out, wrapInCondition(getLocalName(field)+" == nil", format("%s = %s", getLocalName(field), missingValue))...)
+        }
+    }
+
+    return out
+}
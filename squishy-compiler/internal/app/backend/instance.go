@@ -0,0 +1,21 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Instances are appended to sharedInstances and only their index goes into buffer.
+func getWriteCallForInstance(buff string, value string) string {
+    return format("writer.write_instance(%s, cursor, %s, sharedInstances)", buff, value)
+}
+
+// Instances of wrong class are read as nil.
+func getReadCallForInstance(_type *types.Type) string {
+    if _type.InstanceClass != "" {
+        return format("reader.read_instance(buff, cursor, sharedInstances, \"%s\")", _type.InstanceClass)
+    }
+
+    return "reader.read_instance(buff, cursor, sharedInstances)"
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Every optional field gets a bit in presence masks, one u8 mask per 8 optional fields.
+func getOptionalFieldBits(fields []*types.Field) (int, map[*types.Field]int) {
+    bits := map[*types.Field]int{}
+
+    for _, field := range fields {
+        if field.Type.IsOptional {
+            bits[field] = len(bits)
+        }
+    }
+
+    return (len(bits) + 7) / 8, bits
+}
+
+func getPresenceMaskName(bit int) string {
+    return format("presenceMask%d", bit/8+1)
+}
+
+func getPresenceMaskValue(bit int) int {
+    return 1 << (bit % 8)
+}
+
+func getWriteStringForPresenceMasks(fields []*types.Field) []string {
+    maskCount, bits := getOptionalFieldBits(fields)
+    out := []string{}
+
+    for i := 0; i < maskCount; i++ {
+        out = append(out, format("local %s = 0", getPresenceMaskName(i*8)))
+    }
+
+    for _, field := range fields {
+        bit, isOptional := bits[field]
+        if !isOptional {
+            continue
+        }
+
+        maskName := getPresenceMaskName(bit)
+        out = append(out, format("if input.%s ~= nil then %s = bit32.bor(%s, %d) end", field.Name, maskName, maskName, getPresenceMaskValue(bit)))
+    }
+
+    for i := 0; i < maskCount; i++ {
+        out = append(out, format("cursor = writer.write_u8(sharedBuffer, cursor, %s)", getPresenceMaskName(i*8)))
+    }
+
+    return out
+}
+
+func getReadStringForPresenceMasks(fields []*types.Field) []string {
+    maskCount, _ := getOptionalFieldBits(fields)
+    out := []string{}
+
+    for i := 0; i < maskCount; i++ {
+        maskName := getPresenceMaskName(i * 8)
+        out = append(out, "local "+maskName)
+        out = append(out, format("cursor, %s = reader.read_u8(buff, cursor)", maskName))
+    }
+
+    return out
+}
+
+func wrapInCondition(condition string, lines ...string) []string {
+    out := []string{"if " + condition + " then"}
+
+    for _, line := range lines {
+        out = append(out, "    "+line)
+    }
+
+    return append(out, "end")
+}
@@ -0,0 +1,138 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Bools, ranged ints and enums of packed structs go through the bit writer, everything else stays byte aligned.
+func isPackableType(_type *types.Type) bool {
+    if _type.IsArray || _type.IsMap {
+        return false
+    }
+
+    return _type.Name == "bool" || _type.Range != nil || _type.IsReferenceToAnEnum
+}
+
+func getEnumBits(_enum *types.Enum) int {
+    bits := 1
+
+    for (1 << bits) < len(_enum.Members) {
+        bits++
+    }
+
+    return bits
+}
+
+func getWriteBitsStringForField(field *types.Field, enums map[string]*types.Enum) []string {
+    _type := field.Type
+
+    if _type.Range != nil {
+        return []string{format("writer.write_rangeBits(bitWriter, %s, %d, %d, %d)", getRangeCheck("input."+field.Name, _type.Range), _type.Range.Min, _type.Range.Max, _type.Range.Bits)}
+    }
+
+    if _type.IsReferenceToAnEnum {
+        return []string{
+            getEnumGuard(_type.Name, "input."+field.Name),
+            format("writer.write_bits(bitWriter, enumIndexes_%s[input.%s], %d)", _type.Name, field.Name, getEnumBits(enums[_type.Name])),
+        }
+    }
+
+    return []string{format("writer.write_bits(bitWriter, if input.%s then 1 else 0, 1)", field.Name)}
+}
+
+func getReadBitsStringForField(field *types.Field, enums map[string]*types.Enum) string {
+    _type := field.Type
+
+    if _type.Range != nil {
+        read := format("reader.read_rangeBits(bitReader, %d, %d, %d)", _type.Range.Min, _type.Range.Max, _type.Range.Bits)
+        return format("%s = %s", getLocalName(field), getRangeCheck(read, _type.Range))
+    }
+
+    if _type.IsReferenceToAnEnum {
+        return format("%s = enumValues_%s[reader.read_bits(bitReader, %d) + 1]", getLocalName(field), _type.Name, getEnumBits(enums[_type.Name]))
+    }
+
+    return format("%s = reader.read_bits(bitReader, 1) == 1", getLocalName(field))
+}
+
+func packedStructToWriteString(_struct *types.Struct, enums map[string]*types.Enum) []string {
+    out := []string{"local bitWriter = writer.begin_bits(sharedBuffer, cursor)"}
+
+    for _, field := range _struct.Fields {
+        if field.Type.IsOptional {
+            out = append(out, format("writer.write_bits(bitWriter, if input.%s ~= nil then 1 else 0, 1)", field.Name))
+        }
+    }
+
+    for _, field := range _struct.Fields {
+        if !isPackableType(field.Type) {
+            continue
+        }
+
+        if field.Type.IsOptional {
+            out = append(out, wrapInCondition(format("input.%s ~= nil", field.Name), getWriteBitsStringForField(field, enums)...)...)
+            continue
+        }
+
+        out = append(out, getWriteBitsStringForField(field, enums)...)
+    }
+
+    out = append(out, "cursor = writer.end_bits(bitWriter)")
+
+    for _, field := range _struct.Fields {
+        if isPackableType(field.Type) {
+            continue
+        }
+
+        if field.Type.IsOptional {
+            out = append(out, wrapInCondition(format("input.%s ~= nil", field.Name), getWriteStringForField(field))...)
+            continue
+        }
+
+        out = append(out, getWriteStringForField(field))
+    }
+
+    return out
+}
+
+func packedStructToReadString(_struct *types.Struct, enums map[string]*types.Enum) []string {
+    out := []string{"local bitReader = reader.begin_bits(buff, cursor)"}
+
+    for _, field := range _struct.Fields {
+        if field.Type.IsOptional {
+            out = append(out, format("local %sIsPresent = reader.read_bits(bitReader, 1) == 1", field.Name))
+        }
+    }
+
+    for _, field := range _struct.Fields {
+        if !isPackableType(field.Type) {
+            continue
+        }
+
+        if field.Type.IsOptional {
+            out = append(out, wrapInCondition(field.Name+"IsPresent", getReadBitsStringForField(field, enums))...)
+            continue
+        }
+
+        out = append(out, getReadBitsStringForField(field, enums))
+    }
+
+    out = append(out, "cursor = reader.end_bits(bitReader)")
+
+    for _, field := range _struct.Fields {
+        if isPackableType(field.Type) {
+            continue
+        }
+
+        if field.Type.IsOptional {
+            out = append(out, wrapInCondition(field.Name+"IsPresent", getReadStringForField(field))...)
+            continue
+        }
+
+        out = append(out, getReadStringForField(field))
+    }
+
+    return append(out, getReadStringForDefaults(_struct.Fields)...)
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+    "strconv"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Consecutive ranged fields of structs share a bit writer and take exactly their bits, so fields keep
+// their declaration order. Ranged values anywhere else (lists, maps, unions, evolvable structs) and
+// quantized values are sent with the smallest byte aligned unsigned type holding their bits.
+func getWireTypeForBits(bits int) string {
+    if bits <= 8 {
+        return "u8"
+    } else if bits <= 16 {
+        return "u16"
+    }
+
+    return "u32"
+}
+
+func formatNumber(number float64) string {
+    return strconv.FormatFloat(number, 'g', -1, 64)
+}
+
+func usesRanges(scheme *types.Scheme) bool {
+    uses := false
+
+    walkSchemeTypes(scheme, func(_type *types.Type) {
+        if _type.Range != nil {
+            uses = true
+        }
+    })
+
+    return uses
+}
+
+// Values are checked on both sides, writes of values out of range and reads of invalid encodings error.
+func getRangeCheckFunctions() []string {
+    return []string{
+        "function checkRange(value : number, min : number, max : number) : number",
+        "    if value < min or value > max then",
+        "        error(\"Ranged value \" .. tostring(value) .. \" is out of range \" .. min .. \"..\" .. max .. \".\")",
+        "    end",
+        "    return value",
+        "end\n",
+        "function checkReadRange(min : number, max : number, cursor : number, value : number) : (number, number)",
+        "    return cursor, checkRange(value, min, max)",
+        "end\n",
+    }
+}
+
+func getRangeCheck(value string, _range *types.Range) string {
+    return format("checkRange(%s, %d, %d)", value, _range.Min, _range.Max)
+}
+
+func isBitExactRange(field *types.Field) bool {
+    return field.Type.Range != nil && !field.Type.IsArray && !field.Type.IsMap
+}
+
+func getWriteStringForRanges(fields []*types.Field, bits map[*types.Field]int) []string {
+    out := []string{}
+
+    for _, field := range fields {
+        if !isBitExactRange(field) {
+            continue
+        }
+
+        if bit, isOptional := bits[field]; isOptional {
+            condition := format("bit32.btest(%s, %d)", getPresenceMaskName(bit), getPresenceMaskValue(bit))
+            out = append(out, wrapInCondition(condition, getWriteBitsStringForField(field, nil)...)...)
+            continue
+        }
+
+        out = append(out, getWriteBitsStringForField(field, nil)...)
+    }
+
+    if len(out) == 0 {
+        return out
+    }
+
+    out = append([]string{"local bitWriter = writer.begin_bits(sharedBuffer, cursor)"}, out...)
+
+    return append(out, "cursor = writer.end_bits(bitWriter)")
+}
+
+func getReadStringForRanges(fields []*types.Field, bits map[*types.Field]int) []string {
+    out := []string{}
+
+    for _, field := range fields {
+        if !isBitExactRange(field) {
+            continue
+        }
+
+        if bit, isOptional := bits[field]; isOptional {
+            condition := format("bit32.btest(%s, %d)", getPresenceMaskName(bit), getPresenceMaskValue(bit))
+            out = append(out, wrapInCondition(condition, getReadBitsStringForField(field, nil))...)
+            continue
+        }
+
+        out = append(out, getReadBitsStringForField(field, nil))
+    }
+
+    if len(out) == 0 {
+        return out
+    }
+
+    out = append([]string{"local bitReader = reader.begin_bits(buff, cursor)"}, out...)
+
+    return append(out, "cursor = reader.end_bits(bitReader)")
+}
+
+func getWriteCallForRange(buff string, value string, _range *types.Range) string {
+    return format("writer.write_range(%s, cursor, %s, %d, %d, writer.write_%s)", buff, getRangeCheck(value, _range), _range.Min, _range.Max, getWireTypeForBits(_range.Bits))
+}
+
+func getReadCallForRange(_range *types.Range) string {
+    return format("checkReadRange(%d, %d, reader.read_range(buff, cursor, %d, %d, reader.read_%s))", _range.Min, _range.Max, _range.Min, _range.Max, getWireTypeForBits(_range.Bits))
+}
+
+// Public Functions
+func RangeToFunctions(scheme *types.Scheme) []string {
+    if !usesRanges(scheme) {
+        return []string{}
+    }
+
+    return getRangeCheckFunctions()
+}
+
+func getWriteCallForQuantization(name string, buff string, value string, q *types.Quantization) string {
+    return format("writer.write_%s(%s, cursor, %s, %s, %s, %s, writer.write_%s)", name, buff, value,
+        formatNumber(q.Min), formatNumber(q.Max), formatNumber(q.Precision), getWireTypeForBits(q.Bits))
+}
+
+func getReadCallForQuantization(name string, q *types.Quantization) string {
+    return format("reader.read_%s(buff, cursor, %s, %s, %s, reader.read_%s)", name,
+        formatNumber(q.Min), formatNumber(q.Max), formatNumber(q.Precision), getWireTypeForBits(q.Bits))
+}
@@ -0,0 +1,58 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/config"
+)
+
+// Functions
+
+// Values of 'any' can be nested tables, so they count as recursion too.
+func hasRecursion(scheme *types.Scheme) bool {
+    if usesAnyType(scheme) {
+        return true
+    }
+
+    for _, _struct := range scheme.Structs {
+        if _struct.Recursive && len(_struct.TypeParameters) == 0 {
+            return true
+        }
+    }
+
+    for _, _union := range scheme.Unions {
+        if _union.Recursive {
+            return true
+        }
+    }
+
+    return false
+}
+
+func getRecursionVariables() []string {
+    return []string{
+        "local readDepth = 0",
+        format("local maxReadDepth = %d -- Reads of recursive structs and reads and writes of any tables nested deeper than this error, see scheme.setMaxDepth.", config.DefaultMaxReadDepth),
+    }
+}
+
+// Read functions of recursive structs and unions count how deep they are nested.
+func getReadStringForDepthEnter() []string {
+    return []string{
+        "    readDepth += 1",
+        "    if readDepth > maxReadDepth then",
+        "        error(\"Payload is nested deeper than \" .. maxReadDepth .. \" levels.\")",
+        "    end",
+    }
+}
+
+func getReadStringForDepthExit() string {
+    return "    readDepth -= 1"
+}
+
+func getSetMaxDepthFunction() []string {
+    return []string{
+        "function scheme.setMaxDepth(depth : number)",
+        "    maxReadDepth = depth",
+        "end",
+    }
+}
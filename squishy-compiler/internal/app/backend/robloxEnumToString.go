@@ -0,0 +1,57 @@
+package backend
+
+import (
+    "slices"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Roblox enum families get generated functions like declarations do.
+func getDeclarationName(_type *types.Type) string {
+    if _type.RobloxEnum != "" {
+        return "Enum_" + _type.RobloxEnum
+    }
+
+    return _type.Name
+}
+
+// Public Functions
+func RobloxEnumsToFunctions(scheme *types.Scheme) []string {
+    families := map[string]bool{}
+
+    walkSchemeTypes(scheme, func(_type *types.Type) {
+        if _type.RobloxEnum != "" {
+            families[_type.RobloxEnum] = true
+        }
+    })
+
+    sortedFamilies := []string{}
+    for family := range families {
+        sortedFamilies = append(sortedFamilies, family)
+    }
+
+    slices.Sort(sortedFamilies)
+
+    out := []string{}
+
+    for _, family := range sortedFamilies {
+        out = append(out, format("local enumItems_%s = {}", family))
+        out = append(out, format("for _, item in Enum.%s:GetEnumItems() do", family))
+        out = append(out, format("    enumItems_%s[item.Value] = item", family))
+        out = append(out, "end\n")
+
+        out = append(out, format("function write_Enum_%s(cursor : number, input : Enum.%s) : number", family, family))
+        out = append(out, "    return writer.write_u16(sharedBuffer, cursor, input.Value)")
+        out = append(out, "end\n")
+
+        out = append(out, format("function read_Enum_%s(buff : buffer, cursor : number) : (number, Enum.%s)", family, family))
+        out = append(out, "    local value")
+        out = append(out, "    cursor, value = reader.read_u16(buff, cursor)")
+        out = append(out, format("    return cursor, enumItems_%s[value]", family))
+        out = append(out, "end\n")
+    }
+
+    return out
+}
@@ -13,108 +13,198 @@ func format(str string, a ...any) string {
     return fmt.Sprintf(str, a...)
 }
 
-func getReadStringForArray(name string, _type *types.Type) string {
+// Nested arrays and maps are read through closures with the same signature as the runtime readers.
+func getReadFunctionForType(_type *types.Type) string {
+    if _type.IsArray || _type.IsMap || _type.Range != nil || _type.Quantization != nil || _type.Name == "instance" {
+        return format("function(buff, cursor) return %s end", getReadCallForType(_type))
+    }
+
+    if language.DefaultTypes[_type.Name] && _type.Name != "any" {
+        return "reader.read_" + _type.Name
+    }
+
+    return "read_" + getDeclarationName(_type)
+}
+
+func getReadCallForArray(_type *types.Type) string {
     if !_type.IsArray {
         return ""
     }
 
-    if _type.Name == "bool" {
+    element := _type.Element
+    varintString := ""
+
+    if _type.IsVarintLength {
+        varintString = "v"
+    }
+
+    if element.Name == "bool" && !element.IsArray && !element.IsMap {
         if _type.ArraySize <= 0 {
-            return format("cursor, %s = reader.read_dynamicBoolArray(sharedBuffer, cursor)", name)
+            return format("reader.read_%sdynamicBoolArray(buff, cursor)", varintString)
         } else {
-            return format("cursor, %s = reader.read_boolArray(sharedBuffer, cursor, %d)", name, _type.ArraySize)
+            return format("reader.read_boolArray(buff, cursor, %d)", _type.ArraySize)
         }
     }
 
-    readFunction := ""
-
-    if language.DefaultTypes[_type.Name] {
-        readFunction = "reader.read_" + _type.Name
-    } else {
-        readFunction = "read_" + _type.Name
-    }
+    readFunction := getReadFunctionForType(element)
 
     if _type.ArraySize <= 0 {
-        return format("cursor, %s = reader.read_dynamicArray(buff, cursor, %s)", name, readFunction)
+        return format("reader.read_%sdynamicArray(buff, cursor, %s)", varintString, readFunction)
     } else {
-        return format("cursor, %s = reader.read_array(buff, cursor, %s, %d)", name, readFunction, _type.ArraySize, )
+        return format("reader.read_array(buff, cursor, %s, %d)", readFunction, _type.ArraySize)
     }
 }
 
-func getReadStringForMap(name string, _type *types.Type) string {
+func getReadCallForMap(_type *types.Type) string {
     if !_type.IsMap {
         return ""
     }
 
-    readFunction := ""
+    readFunction := getReadFunctionForType(_type.Element)
 
-    if language.DefaultTypes[_type.Name] {
-        readFunction = "reader.read_" + _type.Name
-    } else {
-        readFunction = "read_" + _type.Name
+    if _type.IsSet {
+        return format("reader.read_set(buff, cursor, %s)", readFunction)
+    }
+
+    // Typed keys go after the value function, maps without one keep string keys.
+    if _type.Key != nil {
+        readFunction += ", " + getReadFunctionForType(_type.Key)
     }
 
     shortMapString := ""
 
     if _type.IsShortMap {
         shortMapString = "s"
+    } else if _type.IsVarintLength {
+        shortMapString = "v"
     }
-    
-    out := format("cursor, %s = reader.read_%smap(buff, cursor, %s)", name, shortMapString, readFunction)
+
+    out := format("reader.read_%smap(buff, cursor, %s)", shortMapString, readFunction)
 
     return out
 }
 
-func getReadStringForADefaultType(name string, _type *types.Type) string {
-    return format("cursor, %s = reader.read_%s(buff, cursor)", name, _type.Name)
-}
+func getReadCallForType(_type *types.Type) string {
+    if _type.IsArray {
+        return getReadCallForArray(_type)
+    } else if _type.IsMap {
+        return getReadCallForMap(_type)
+    }
 
-func getReadStringForField(field *types.Field) string {
-    if field.Type.IsArray {
-        return getReadStringForArray(field.Name, field.Type)
-    } else if field.Type.IsMap {
-        return getReadStringForMap(field.Name, field.Type)
+    if _type.Range != nil {
+        return getReadCallForRange(_type.Range)
     }
 
-    if language.DefaultTypes[field.Type.Name] {
-        return getReadStringForADefaultType(field.Name, field.Type)
+    if _type.Quantization != nil {
+        return getReadCallForQuantization(_type.Name, _type.Quantization)
     }
 
-    return format("cursor, %s = read_%s(buff, cursor)", field.Name, field.Type.Name)
+    if _type.Name == "instance" {
+        return getReadCallForInstance(_type)
+    }
+
+    if language.DefaultTypes[_type.Name] && _type.Name != "any" {
+        return format("reader.read_%s(buff, cursor)", _type.Name)
+    }
+
+    return format("read_%s(buff, cursor)", getDeclarationName(_type))
+}
+
+// Fields are read into prefixed locals, generated locals never start with '_' so fields can't shadow them.
+func getLocalName(field *types.Field) string {
+    return "_" + field.Name
+}
+
+func getReadStringForField(field *types.Field) string {
+    return format("cursor, %s = %s", getLocalName(field), getReadCallForType(field.Type))
 }
 
 // Public Functions
-func StructToReadString(_struct *types.Struct) ([]string, string) {
+func StructToReadString(_struct *types.Struct, structs map[string]*types.Struct, unions map[string]*types.Union, enums map[string]*types.Enum) ([]string, string) {
     fields := _struct.Fields
 
     returnString := "{ "
     fieldNames := []string{}
+    out := []string{}
+
+    // Base is read by its own function and its fields are copied into result.
+    if _struct.Base != "" {
+        out = append(out, "local inheritedFields")
+        out = append(out, format("cursor, inheritedFields = read_%s(buff, cursor)", _struct.Base))
+
+        for _, field := range _struct.Inherited {
+            returnString = format("%s%s = inheritedFields.%s; ", returnString, field.Name, field.Name)
+        }
+    }
 
     for _, field := range fields {
-        fieldNames = append(fieldNames, field.Name)
-        returnString = format("%s%s = %s; ", returnString, field.Name, field.Name)
+        fieldNames = append(fieldNames, getLocalName(field))
+        returnString = format("%s%s = %s; ", returnString, field.Name, getLocalName(field))
     }
 
-    variableString := "local " + strings.Join(fieldNames, ", ")
+    if len(fieldNames) > 0 {
+        out = append(out, "local "+strings.Join(fieldNames, ", "))
+    }
 
     returnString = returnString + "}"
 
-    out := []string{variableString}
+    if _struct.Packed {
+        return append(out, packedStructToReadString(_struct, enums)...), returnString
+    }
+
+    if isEvolvable(_struct) {
+        return append(out, evolvableStructToReadString(_struct, structs, unions, enums)...), returnString
+    }
+
+    out = append(out, getReadStringForPresenceMasks(fields)...)
+    _, bits := getOptionalFieldBits(fields)
+    run := []*types.Field{}
 
     for _, val := range fields {
+        if isBitExactRange(val) {
+            run = append(run, val)
+            continue
+        }
+
+        out = append(out, getReadStringForRanges(run, bits)...)
+        run = []*types.Field{}
+
+        if bit, isOptional := bits[val]; isOptional {
+            condition := format("bit32.btest(%s, %d)", getPresenceMaskName(bit), getPresenceMaskValue(bit))
+            out = append(out, wrapInCondition(condition, getReadStringForField(val))...)
+            continue
+        }
+
         out = append(out, getReadStringForField(val))
     }
 
+    out = append(out, getReadStringForRanges(run, bits)...)
+    out = append(out, getReadStringForDefaults(fields)...)
+
     return out, returnString
 }
 
-func StructListToReadFunctions(list []string, structs map[string]*types.Struct) []string {
+func StructListToReadFunctions(list []string, structs map[string]*types.Struct, unions map[string]*types.Union, enums map[string]*types.Enum) []string {
     out := []string{}
 
     for _, name := range list {
+        if _, isStruct := structs[name]; !isStruct {
+            continue
+        }
+
         out = append(out, format("function read_%s(buff : buffer, cursor : number) : (number, %s)", name, name))
-        body, returnString := StructToReadString(structs[name])
+
+        if structs[name].Recursive {
+            out = append(out, getReadStringForDepthEnter()...)
+        }
+
+        body, returnString := StructToReadString(structs[name], structs, unions, enums)
         out = append(out, "    "+strings.Join(body, "\n    "))
+
+        if structs[name].Recursive {
+            out = append(out, getReadStringForDepthExit())
+        }
+
         out = append(out, "    return cursor, "+returnString)
         out = append(out, "end\n")
     }
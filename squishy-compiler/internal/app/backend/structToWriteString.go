@@ -8,95 +8,165 @@ import (
 )
 
 // Functions
-func getWriteStringForArray(name string, _type *types.Type) string {
+
+// Nested arrays and maps are written through closures with the same signature as the runtime writers.
+func getWriteFunctionForType(_type *types.Type) string {
+    if _type.IsArray || _type.IsMap || _type.Range != nil || _type.Quantization != nil || _type.Name == "instance" {
+        return format("function(buff, cursor, value) return %s end", getWriteCallForType("buff", "value", _type))
+    }
+
+    if language.DefaultTypes[_type.Name] && _type.Name != "any" {
+        return "writer.write_" + _type.Name
+    }
+
+    return "write_" + getDeclarationName(_type)
+}
+
+func getWriteCallForArray(buff string, value string, _type *types.Type) string {
     if !_type.IsArray {
         return ""
     }
 
-    if _type.Name == "bool" {
+    element := _type.Element
+    varintString := ""
+
+    if _type.IsVarintLength {
+        varintString = "v"
+    }
+
+    if element.Name == "bool" && !element.IsArray && !element.IsMap {
         if _type.ArraySize <= 0 {
-            return format("cursor = writer.write_dynamicBoolArray(sharedBuffer, cursor, input.%s)", name)
+            return format("writer.write_%sdynamicBoolArray(%s, cursor, %s)", varintString, buff, value)
         } else {
-            return format("cursor = writer.write_boolArray(sharedBuffer, cursor, input.%s, %d)", name, _type.ArraySize)
+            return format("writer.write_boolArray(%s, cursor, %s, %d)", buff, value, _type.ArraySize)
         }
     }
 
-    writeFunction := ""
-
-    if language.DefaultTypes[_type.Name] {
-        writeFunction = "writer.write_" + _type.Name
-    } else {
-        writeFunction = "write_" + _type.Name
-    }
+    writeFunction := getWriteFunctionForType(element)
 
     if _type.ArraySize <= 0 {
-        return format("cursor = writer.write_dynamicArray(sharedBuffer, cursor, input.%s, %s)", name, writeFunction)
+        return format("writer.write_%sdynamicArray(%s, cursor, %s, %s)", varintString, buff, value, writeFunction)
     } else {
-        return format("cursor = writer.write_array(sharedBuffer, cursor, input.%s, %s, %d)", name, writeFunction, _type.ArraySize)
+        return format("writer.write_array(%s, cursor, %s, %s, %d)", buff, value, writeFunction, _type.ArraySize)
     }
 }
 
-func getWriteStringForMap(name string, _type *types.Type) string {
+func getWriteCallForMap(buff string, value string, _type *types.Type) string {
     if !_type.IsMap {
         return ""
     }
 
-    writeFunction := ""
+    writeFunction := getWriteFunctionForType(_type.Element)
 
-    if language.DefaultTypes[_type.Name] {
-        writeFunction = "writer.write_" + _type.Name
-    } else {
-        writeFunction = "write_" + _type.Name
+    // Sets send a u16 count and their elements, values are always true.
+    if _type.IsSet {
+        return format("writer.write_set(%s, cursor, %s, %s)", buff, value, writeFunction)
+    }
+
+    // Typed keys go after the value function, maps without one keep string keys.
+    if _type.Key != nil {
+        writeFunction += ", " + getWriteFunctionForType(_type.Key)
     }
 
     shortMapString := ""
 
     if _type.IsShortMap {
         shortMapString = "s"
+    } else if _type.IsVarintLength {
+        shortMapString = "v"
     }
 
-    out := format("cursor = writer.write_%smap(sharedBuffer, cursor, input.%s, %s)", shortMapString, name, writeFunction)
+    out := format("writer.write_%smap(%s, cursor, %s, %s)", shortMapString, buff, value, writeFunction)
 
     return out
 }
 
-func getWriteStringForADefaultType(name string, _type *types.Type) string {
-    return format("cursor = writer.write_%s(sharedBuffer, cursor, input.%s)", _type.Name, name)
-}
+func getWriteCallForType(buff string, value string, _type *types.Type) string {
+    if _type.IsArray {
+        return getWriteCallForArray(buff, value, _type)
+    } else if _type.IsMap {
+        return getWriteCallForMap(buff, value, _type)
+    }
 
-func getWriteStringForField(field *types.Field) string {
-    if field.Type.IsArray {
-        return getWriteStringForArray(field.Name, field.Type)
-    } else if field.Type.IsMap {
-        return getWriteStringForMap(field.Name, field.Type)
+    if _type.Range != nil {
+        return getWriteCallForRange(buff, value, _type.Range)
+    }
+
+    if _type.Quantization != nil {
+        return getWriteCallForQuantization(_type.Name, buff, value, _type.Quantization)
     }
 
-    if language.DefaultTypes[field.Type.Name] {
-        return getWriteStringForADefaultType(field.Name, field.Type)
+    if _type.Name == "instance" {
+        return getWriteCallForInstance(buff, value)
     }
 
-    return format("cursor = write_%s(cursor, input.%s)", field.Type.Name, field.Name)
+    if language.DefaultTypes[_type.Name] && _type.Name != "any" {
+        return format("writer.write_%s(%s, cursor, %s)", _type.Name, buff, value)
+    }
+
+    return format("write_%s(cursor, %s)", getDeclarationName(_type), value)
+}
+
+func getWriteStringForField(field *types.Field) string {
+    return "cursor = " + getWriteCallForType("sharedBuffer", "input."+field.Name, field.Type)
 }
 
 // Public Functions
-func StructToWriteString(_struct *types.Struct) []string {
+func StructToWriteString(_struct *types.Struct, enums map[string]*types.Enum) []string {
     out := []string{}
 
+    // Base fields go first, written by base's own function.
+    if _struct.Base != "" {
+        out = append(out, format("cursor = write_%s(cursor, input)", _struct.Base))
+    }
+
+    if _struct.Packed {
+        return append(out, packedStructToWriteString(_struct, enums)...)
+    }
+
+    if isEvolvable(_struct) {
+        return append(out, evolvableStructToWriteString(_struct)...)
+    }
+
     fields := _struct.Fields
 
+    out = append(out, getWriteStringForPresenceMasks(fields)...)
+    _, bits := getOptionalFieldBits(fields)
+    run := []*types.Field{}
+
     for _, val := range fields {
+        if isBitExactRange(val) {
+            run = append(run, val)
+            continue
+        }
+
+        out = append(out, getWriteStringForRanges(run, bits)...)
+        run = []*types.Field{}
+
+        if bit, isOptional := bits[val]; isOptional {
+            condition := format("bit32.btest(%s, %d)", getPresenceMaskName(bit), getPresenceMaskValue(bit))
+            out = append(out, wrapInCondition(condition, getWriteStringForField(val))...)
+            continue
+        }
+
         out = append(out, getWriteStringForField(val))
     }
 
+    out = append(out, getWriteStringForRanges(run, bits)...)
+
     return out
 }
 
-func StructListToWriteFunctions(list []string, structs map[string]*types.Struct) []string {
+func StructListToWriteFunctions(list []string, structs map[string]*types.Struct, enums map[string]*types.Enum) []string {
     out := []string{}
 
     for _, name := range list {
+        if _, isStruct := structs[name]; !isStruct {
+            continue
+        }
+
         out = append(out, format("function write_%s(cursor : number, input : %s) : number", name, name))
-        out = append(out, "    "+strings.Join(StructToWriteString(structs[name]), "\n    "))
+        out = append(out, "    "+strings.Join(StructToWriteString(structs[name], enums), "\n    "))
         out = append(out, "    return cursor")
         out = append(out, "end\n")
     }
@@ -0,0 +1,95 @@
+package backend
+
+import (
+    "strings"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+
+// Arms are written like a field named value, so field generators can be reused as is.
+func getArmAsValueField(arm *types.Field) *types.Field {
+    return &types.Field{
+        Name: "value",
+        Type: arm.Type,
+    }
+}
+
+// Public Functions
+func UnionToWriteString(_union *types.Union) []string {
+    out := []string{}
+
+    for i, arm := range _union.Arms {
+        keyword := "elseif"
+        if i == 0 {
+            keyword = "if"
+        }
+
+        out = append(out, format("%s input.kind == \"%s\" then", keyword, arm.Name))
+        out = append(out, format("    cursor = writer.write_u8(sharedBuffer, cursor, %d)", i))
+        out = append(out, "    "+getWriteStringForField(getArmAsValueField(arm)))
+    }
+
+    out = append(out, "else")
+    out = append(out, format("    error(\"Unknown kind '\" .. tostring(input.kind) .. \"' for union %s.\")", _union.Name))
+    out = append(out, "end")
+
+    return out
+}
+
+func UnionToReadString(_union *types.Union) ([]string, string) {
+    out := []string{"local index, kind, value"}
+    out = append(out, "cursor, index = reader.read_u8(buff, cursor)")
+
+    for i, arm := range _union.Arms {
+        keyword := "elseif"
+        if i == 0 {
+            keyword = "if"
+        }
+
+        out = append(out, format("%s index == %d then", keyword, i))
+        out = append(out, format("    kind = \"%s\"", arm.Name))
+        out = append(out, "    "+getReadStringForField(getArmAsValueField(arm)))
+    }
+
+    out = append(out, "else")
+    out = append(out, format("    error(\"Unknown kind index '\" .. index .. \"' for union %s.\")", _union.Name))
+    out = append(out, "end")
+
+    return out, "{ kind = kind; value = value; }"
+}
+
+func UnionListToFunctions(list []string, unions map[string]*types.Union) []string {
+    out := []string{}
+
+    for _, name := range list {
+        _union, isUnion := unions[name]
+        if !isUnion {
+            continue
+        }
+
+        out = append(out, format("function write_%s(cursor : number, input : %s) : number", name, name))
+        out = append(out, "    "+strings.Join(UnionToWriteString(_union), "\n    "))
+        out = append(out, "    return cursor")
+        out = append(out, "end\n")
+
+        out = append(out, format("function read_%s(buff : buffer, cursor : number) : (number, %s)", name, name))
+
+        if _union.Recursive {
+            out = append(out, getReadStringForDepthEnter()...)
+        }
+
+        body, returnString := UnionToReadString(_union)
+        out = append(out, "    "+strings.Join(body, "\n    "))
+
+        if _union.Recursive {
+            out = append(out, getReadStringForDepthExit())
+        }
+
+        out = append(out, "    return cursor, "+returnString)
+        out = append(out, "end\n")
+    }
+
+    return out
+}
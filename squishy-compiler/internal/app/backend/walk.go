@@ -0,0 +1,38 @@
+package backend
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Functions
+func walkType(_type *types.Type, visit func(*types.Type)) {
+    visit(_type)
+
+    if _type.Element != nil {
+        walkType(_type.Element, visit)
+    }
+
+    if _type.Key != nil {
+        walkType(_type.Key, visit)
+    }
+}
+
+// Visits every field type of scheme, elements and keys of arrays and maps included.
+// Generic structs are skipped, their instances have the concrete types.
+func walkSchemeTypes(scheme *types.Scheme, visit func(*types.Type)) {
+    for _, _struct := range scheme.Structs {
+        if len(_struct.TypeParameters) > 0 {
+            continue
+        }
+
+        for _, field := range _struct.Fields {
+            walkType(field.Type, visit)
+        }
+    }
+
+    for _, _union := range scheme.Unions {
+        for _, arm := range _union.Arms {
+            walkType(arm.Type, visit)
+        }
+    }
+}
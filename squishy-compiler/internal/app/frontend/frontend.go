@@ -22,6 +22,10 @@
 package frontend
 
 import (
+    "fmt"
+    "path/filepath"
+    "strings"
+
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/frontend/lexer"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/frontend/parser"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
@@ -42,6 +46,9 @@ import (
     *   @privatevariable myParser : parser.Parser ;; Parser.
     @publicvariables
     *   @publicvariable Result : types.Scheme ;; Result of lexing and parsing
+    @privatemethods
+    *   @privatemethod importFile
+    *   @privatemethod importAll
     @publicmethods
     *   @publicmethod Work
     *   @publicmethod Debug
@@ -74,8 +81,13 @@ func New(path string) (*Frontend, *errors.StackError) {
         myLexer:  myLexer,
         myParser: myParser,
         Result: &types.Scheme{
-            Structs: make(map[string]*types.Struct),
-            Exports: "",
+            Structs:   make(map[string]*types.Struct),
+            Enums:     make(map[string]*types.Enum),
+            Unions:    make(map[string]*types.Union),
+            Constants: make(map[string]*types.Constant),
+            Aliases:   make(map[string]*types.TypeAlias),
+            Exports:   []string{},
+            Name:      "",
         },
     }, nil
 }
@@ -89,12 +101,86 @@ func NewFromString(input string) *Frontend {
         myLexer:  myLexer,
         myParser: myParser,
         Result: &types.Scheme{
-            Structs: make(map[string]*types.Struct),
-            Exports: "",
+            Structs:   make(map[string]*types.Struct),
+            Enums:     make(map[string]*types.Enum),
+            Unions:    make(map[string]*types.Union),
+            Constants: make(map[string]*types.Constant),
+            Aliases:   make(map[string]*types.TypeAlias),
+            Exports:   []string{},
+            Name:      "",
         },
     }
 }
 
+// Private Methods
+func (frontend *Frontend) importFile(path string, importPath []string, importing map[string]bool, imported map[string]bool) *errors.StackError {
+    key, absErr := filepath.Abs(path)
+    if absErr != nil {
+        return errors.New(errors.EmptyError, absErr.Error())
+    }
+
+    if importing[key] {
+        importPath = append(importPath, path)
+        return errors.New(errors.ImportCycle, strings.Join(importPath, " -> "))
+    }
+
+    if imported[key] {
+        return nil
+    }
+
+    if _, err := file.HasAnyValidExtension(path, config.DefaultExpectedFileExtensions); err != nil {
+        return err
+    }
+
+    fileContents, err := file.FileToString(path)
+    if err != nil {
+        return err
+    }
+
+    myLexer := lexer.NewWithFilename(fileContents, path)
+    if err2 := myLexer.Scan(); err2 != nil {
+        return err2
+    }
+
+    myParser := parser.NewWithScheme(myLexer, frontend.Result)
+
+    importing[key] = true
+    if err3 := frontend.importAll(myParser, path, append(importPath, path), importing, imported); err3 != nil {
+        return err3
+    }
+    delete(importing, key)
+
+    if len(myLexer.ExportReferences) > 0 {
+        ui.Log(config.GRANDMASTER, "warning", fmt.Sprintf("Exports in imported file '%s' are ignored.", path))
+    }
+
+    if err4 := myParser.ParseDeclarations(); err4 != nil {
+        return err4
+    }
+
+    imported[key] = true
+
+    return nil
+}
+
+// Imports are resolved relative to the importing file.
+func (frontend *Frontend) importAll(myParser *parser.Parser, from string, importPath []string, importing map[string]bool, imported map[string]bool) *errors.StackError {
+    imports, err := myParser.ParseImports()
+    if err != nil {
+        return err
+    }
+
+    for _, importedPath := range imports {
+        path := filepath.Join(filepath.Dir(from), importedPath)
+
+        if err2 := frontend.importFile(path, importPath, importing, imported); err2 != nil {
+            return err2
+        }
+    }
+
+    return nil
+}
+
 // Public Methods
 func (frontend *Frontend) WorkFromString(input string) *errors.StackError {
     frontend.myLexer = lexer.New(input)
@@ -102,7 +188,10 @@ func (frontend *Frontend) WorkFromString(input string) *errors.StackError {
     if err1 != nil {
         return err1
     }
-    frontend.myParser = parser.New(frontend.myLexer)
+    frontend.myParser = parser.NewWithScheme(frontend.myLexer, frontend.Result)
+    if err := frontend.importAll(frontend.myParser, "", []string{"<input>"}, map[string]bool{}, map[string]bool{}); err != nil {
+        return err
+    }
     err2 := frontend.myParser.Parse()
     if err2 != nil {
         return err2
@@ -119,12 +208,23 @@ func (frontend *Frontend) Work() *errors.StackError {
         return err
     }
 
-    frontend.myLexer = lexer.New(fileContents)
+    frontend.myLexer = lexer.NewWithFilename(fileContents, frontend.path)
     err2 := frontend.myLexer.Scan()
     if err2 != nil {
         return err2
     }
-    frontend.myParser = parser.New(frontend.myLexer)
+    frontend.myParser = parser.NewWithScheme(frontend.myLexer, frontend.Result)
+
+    key, absErr := filepath.Abs(frontend.path)
+    if absErr != nil {
+        return errors.New(errors.EmptyError, absErr.Error())
+    }
+
+    importing := map[string]bool{key: true}
+    if err := frontend.importAll(frontend.myParser, frontend.path, []string{frontend.path}, importing, map[string]bool{}); err != nil {
+        return err
+    }
+
     err3 := frontend.myParser.Parse()
     if err3 != nil {
         return err3
@@ -132,6 +232,11 @@ func (frontend *Frontend) Work() *errors.StackError {
 
     frontend.Result = &frontend.myParser.Result
 
+    // A module with multiple exports is named after its source file.
+    if len(frontend.Result.Exports) > 1 {
+        frontend.Result.Name = strings.TrimSuffix(filepath.Base(frontend.path), filepath.Ext(frontend.path))
+    }
+
     return nil
 }
 
@@ -45,6 +45,13 @@ var tokenIsToString = map[int]string{
     6: "Invalid",
     7: "Field Name",
     8: "Export Name",
+    9: "Comment",
+    10: "Enum Name",
+    11: "Union Name",
+    12: "String",
+    13: "Constant Name",
+    14: "Float",
+    15: "Type Alias Name",
 }
 
 // Functions
@@ -65,7 +72,16 @@ func castTokenIsToString(token *types.Token) string {
     *   @publicvariable StructReferences : []int ;; Location of struct references in @object:TokenList.
     *   @publicvariable FieldReferences : []int ;; Location of field references in @object:TokenList.
     *   @publicvariable ExportReferences : []int ;; Location of export references in @object:TokenList.
+    *   @publicvariable EnumReferences : []int ;; Location of enum references in @object:TokenList.
+    *   @publicvariable UnionReferences : []int ;; Location of union references in @object:TokenList.
+    *   @publicvariable ImportReferences : []int ;; Location of import references in @object:TokenList.
+    *   @publicvariable ConstantReferences : []int ;; Location of constant references in @object:TokenList.
+    *   @publicvariable PackedReferences : []int ;; Location of packed modifier references in @object:TokenList.
+    *   @publicvariable TypeAliasReferences : []int ;; Location of type alias references in @object:TokenList.
     @privatemethods
+    *   @privatemethod scanDigits
+    *   @privatemethod addToken
+    *   @privatemethod isAfterFieldKeyword
     *   @privatemethod analyzeAndCategorizeToken
     @publicmethods
     *   @publicmethod Scan
@@ -78,6 +94,7 @@ func castTokenIsToString(token *types.Token) string {
     *   @publicmethod StepCursorForward
     *   @publicmethod StepCursorBackward
     *   @publicmethod JumpCursorAhead
+    *   @publicmethod ResetCursor
     *   @publicmethod Expect
     *   @publicmethod Feed
     *   @publicmethod Length
@@ -86,20 +103,32 @@ func castTokenIsToString(token *types.Token) string {
     @brief A custom lexer for Squishy IDL.
 */
 type Lexer struct {
-    s                scanner.Scanner
-    TokenList        []types.Token
-    Cursor           int
-    StructReferences []int
-    FieldReferences  []int
-    ExportReferences []int
+    s                   scanner.Scanner
+    TokenList           []types.Token
+    Cursor              int
+    StructReferences    []int
+    FieldReferences     []int
+    ExportReferences    []int
+    EnumReferences      []int
+    UnionReferences     []int
+    ImportReferences    []int
+    ConstantReferences  []int
+    PackedReferences    []int
+    TypeAliasReferences []int
 }
 
 // Constructor
 func New(input string) *Lexer {
+    return NewWithFilename(input, "")
+}
+
+// Filename is used in positions of tokens, so errors can point to the right file when importing.
+func NewWithFilename(input string, filename string) *Lexer {
     var s scanner.Scanner
     s.Init(strings.NewReader(input))
 
-    s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanComments
+    s.Filename = filename
+    s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanStrings | scanner.ScanComments
     TokenList := []types.Token{}
 
     return &Lexer{
@@ -110,15 +139,62 @@ func New(input string) *Lexer {
 }
 
 // Private Methods
-func (lexer *Lexer) analyzeAndCategorizeToken(tok rune, last *types.Token) (int, *errors.StackError) {
+func (lexer *Lexer) scanDigits() string {
+    digits := ""
+
+    for ch := lexer.s.Peek(); ch >= '0' && ch <= '9'; ch = lexer.s.Peek() {
+        digits += string(lexer.s.Next())
+    }
+
+    return digits
+}
+
+func (lexer *Lexer) addToken(tok rune, text string) *errors.StackError {
+    var last *types.Token = nil
+    if len(lexer.TokenList) > 0 {
+        last = &lexer.TokenList[len(lexer.TokenList)-1]
+    }
+
+    is, err := lexer.analyzeAndCategorizeToken(tok, text, last)
+    if err != nil {
+        return err
+    }
+
+    if is == types.CommentToken {
+        return nil
+    }
+
+    position := lexer.s.Pos()
+
+    lexer.TokenList = append(lexer.TokenList, types.Token{
+        Position:     len(lexer.TokenList),
+        Is:           is,
+        Value:        text,
+        RealPosition: position.String(),
+    })
+
+    return nil
+}
+
+func (lexer *Lexer) isAfterFieldKeyword(index int) bool {
+    return index > 0 && lexer.TokenList[index-1].Is == types.KeywordToken && lexer.TokenList[index-1].Value == "field"
+}
+
+func (lexer *Lexer) analyzeAndCategorizeToken(tok rune, text string, last *types.Token) (int, *errors.StackError) {
     is := types.UnknownToken
     position := lexer.s.Pos()
-    text := lexer.s.TokenText()
 
     // Too nested.
     switch tok {
     case scanner.Ident:
-        if language.Keywords[text] {
+        // Names after field can be keywords too, 'field type u8' names a field.
+        if last != nil && last.Is == types.KeywordToken && last.Value == "field" {
+            is = types.FieldNameToken
+        } else if last != nil && last.Is == types.IntToken && lexer.isAfterFieldKeyword(len(lexer.TokenList)-1) {
+            is = types.FieldNameToken // field 3 name type
+        } else if text == "enum" && lexer.s.Peek() == '<' {
+            is = types.TypeToken // enum<Material>, a Roblox EnumItem
+        } else if language.Keywords[text] {
             is = types.KeywordToken
             switch text {
             case "struct":
@@ -127,15 +203,38 @@ func (lexer *Lexer) analyzeAndCategorizeToken(tok rune, last *types.Token) (int,
                 lexer.FieldReferences = append(lexer.FieldReferences, len(lexer.TokenList))
             case "exports":
                 lexer.ExportReferences = append(lexer.ExportReferences, len(lexer.TokenList))
+            case "enum":
+                lexer.EnumReferences = append(lexer.EnumReferences, len(lexer.TokenList))
+            case "union":
+                lexer.UnionReferences = append(lexer.UnionReferences, len(lexer.TokenList))
+            case "import":
+                lexer.ImportReferences = append(lexer.ImportReferences, len(lexer.TokenList))
+            case "const":
+                lexer.ConstantReferences = append(lexer.ConstantReferences, len(lexer.TokenList))
+            case "packed":
+                lexer.PackedReferences = append(lexer.PackedReferences, len(lexer.TokenList))
+            case "type":
+                lexer.TypeAliasReferences = append(lexer.TypeAliasReferences, len(lexer.TokenList))
             }
         } else if last != nil {
-            switch last.Value {
+            previousKeyword := "" // Field names can look like keywords, only real keywords name what follows them.
+            if last.Is == types.KeywordToken {
+                previousKeyword = last.Value
+            }
+
+            switch previousKeyword {
             case "struct":
                 is = types.StructNameToken
-            case "field":
-                is = types.FieldNameToken
             case "exports":
                 is = types.ExportNameToken
+            case "enum":
+                is = types.EnumNameToken
+            case "union":
+                is = types.UnionNameToken
+            case "const":
+                is = types.ConstantNameToken
+            case "type":
+                is = types.TypeAliasNameToken
             default:
                 is = types.TypeToken
             }
@@ -145,6 +244,10 @@ func (lexer *Lexer) analyzeAndCategorizeToken(tok rune, last *types.Token) (int,
         // But im actualy doing most of parser's job here.
     case scanner.Int:
         is = types.IntToken
+    case scanner.Float:
+        is = types.FloatToken
+    case scanner.String:
+        is = types.StringToken
     case scanner.Comment:
         return types.CommentToken, nil
     default:
@@ -164,28 +267,33 @@ func (lexer *Lexer) analyzeAndCategorizeToken(tok rune, last *types.Token) (int,
 
 // Public Methods
 func (lexer *Lexer) Scan() *errors.StackError {
-    var last *types.Token = nil
     for tok := lexer.s.Scan(); tok != scanner.EOF; tok = lexer.s.Scan() {
-        is, err := lexer.analyzeAndCategorizeToken(tok, last)
-        if err != nil {
-            return err
-        }
+        text := lexer.s.TokenText()
 
-        if is == types.CommentToken {
-            continue
-        }
+        // Scanner can't tell '0.5' from '0..5' with floats on, so dots after ints are handled here.
+        switch {
+        case tok == scanner.Int && lexer.s.Peek() == '.':
+            lexer.s.Next()
 
-        position := lexer.s.Pos()
-        text := lexer.s.TokenText()
+            if lexer.s.Peek() == '.' { // Range
+                lexer.s.Next()
 
-        lexer.TokenList = append(lexer.TokenList, types.Token{
-            Position:     len(lexer.TokenList),
-            Is:           is,
-            Value:        text,
-            RealPosition: position.String(),
-        })
+                if err := lexer.addToken(tok, text); err != nil {
+                    return err
+                }
 
-        last = &lexer.TokenList[len(lexer.TokenList)-1]
+                tok, text = '.', ".."
+            } else { // Float
+                tok, text = scanner.Float, text+"."+lexer.scanDigits()
+            }
+        case text == "." && lexer.s.Peek() == '.':
+            lexer.s.Next()
+            text = ".."
+        }
+
+        if err := lexer.addToken(tok, text); err != nil {
+            return err
+        }
     }
     return nil
 }
@@ -267,6 +375,10 @@ func (lexer *Lexer) JumpCursorAhead(n int) {
     }
 }
 
+func (lexer *Lexer) ResetCursor() {
+    lexer.Cursor = 0
+}
+
 func (lexer *Lexer) Expect(expected string) bool {
     token := lexer.GetAtCursor()
     return token.Is != types.InvalidToken && token.Value == expected
@@ -303,8 +415,14 @@ func (lexer *Lexer) Print() {
     ui.Log(config.APPRENTICE, "info", "Count of struct references: "+strconv.Itoa(len(lexer.StructReferences)))
     ui.Log(config.APPRENTICE, "info", "Count of field references: "+strconv.Itoa(len(lexer.FieldReferences)))
     ui.Log(config.APPRENTICE, "info", "Count of export references: "+strconv.Itoa(len(lexer.ExportReferences)))
-    if len(lexer.ExportReferences) > 1 || len(lexer.ExportReferences) == 0 {
-        ui.Log(config.APPRENTICE, "warning", "Count of export references normally must be 1!")
+    ui.Log(config.APPRENTICE, "info", "Count of enum references: "+strconv.Itoa(len(lexer.EnumReferences)))
+    ui.Log(config.APPRENTICE, "info", "Count of union references: "+strconv.Itoa(len(lexer.UnionReferences)))
+    ui.Log(config.APPRENTICE, "info", "Count of import references: "+strconv.Itoa(len(lexer.ImportReferences)))
+    ui.Log(config.APPRENTICE, "info", "Count of constant references: "+strconv.Itoa(len(lexer.ConstantReferences)))
+    ui.Log(config.APPRENTICE, "info", "Count of packed references: "+strconv.Itoa(len(lexer.PackedReferences)))
+    ui.Log(config.APPRENTICE, "info", "Count of type alias references: "+strconv.Itoa(len(lexer.TypeAliasReferences)))
+    if len(lexer.ExportReferences) == 0 {
+        ui.Log(config.APPRENTICE, "warning", "Count of export references normally must be at least 1!")
     }
     ui.Log(config.FELLOWCRAFT, "info", "Finished printing tokens.")
 }
\ No newline at end of file
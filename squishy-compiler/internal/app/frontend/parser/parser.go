@@ -23,11 +23,15 @@ package parser
 
 import (
     "fmt"
+    "maps"
+    "math"
+    "slices"
     "strconv"
     "strings"
 
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/frontend/lexer"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/language"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/references"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/cli/ui"
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/config"
@@ -50,6 +54,12 @@ func getConcatenatedNames(fields []*types.Field, indexes []int) string {
     return strings.Join(parts, ", ")
 }
 
+func noteReference(field *types.Field, index int,
+    structReferences map[string][]int, enumReferences map[string][]int, unionReferences map[string][]int,
+) {
+    references.NoteTypeReference(field.Type, index, structReferences, enumReferences, unionReferences)
+}
+
 // Public Structs
 
 /*
@@ -57,31 +67,67 @@ func getConcatenatedNames(fields []*types.Field, indexes []int) string {
 
     @privatevariables
     *   @privatevariable myLexer : *lexer.Lexer ;; Pointer to lexer.
+    *   @privatevariable genericStructs : map[string][]string ;; Type parameters of generic structs by name, collected before any type is parsed.
+    *   @privatevariable currentGeneric : string ;; Name of generic struct whose fields are being parsed, its type parameters are in scope.
     @publicvariables
     *   @publicvariable Result : types.Scheme ;; Result of parsing
     @privatemethods
     *   @privatemethod printStructFields
     *   @privatemethod getFieldTypeDescription
     *   @privatemethod printSingleStruct
+    *   @privatemethod printSingleEnum
+    *   @privatemethod printSingleUnion
+    *   @privatemethod isAUnionName
+    *   @privatemethod findDeclaration
+    *   @privatemethod isADeclaredName
+    *   @privatemethod getTypeParameters
+    *   @privatemethod getReferenceNode
+    *   @privatemethod markReferences
+    *   @privatemethod markExtensions
     *   @privatemethod isTokenAValidType
+    *   @privatemethod parseRangeBound
+    *   @privatemethod parseRange
+    *   @privatemethod parseNumber
+    *   @privatemethod parseQuantization
+    *   @privatemethod parseElementType
+    *   @privatemethod parseMapKey
     *   @privatemethod parseMap
     *   @privatemethod parseArray
+    *   @privatemethod parseTypeArguments
     *   @privatemethod parseType
     *   @privatemethod parseField
+    *   @privatemethod validateFieldIds
+    *   @privatemethod parseReserved
+    *   @privatemethod checkReserved
     *   @privatemethod parseFields
+    *   @privatemethod parseConstants
+    *   @privatemethod checkAliasTarget
+    *   @privatemethod parseTypeAliases
+    *   @privatemethod parseEnumMembers
+    *   @privatemethod parseEnums
+    *   @privatemethod parseTypeParameters
+    *   @privatemethod collectGenericStructs
     *   @privatemethod parseStructs
+    *   @privatemethod parseUnionArms
+    *   @privatemethod parseUnions
     *   @privatemethod parseExports
     *   @privatemethod checkPath
+    *   @privatemethod getNeighborNames
+    *   @privatemethod warnDeprecatedFields
     *   @privatemethod detectCycles
     *   @privatemethod semanticAnalyze
     @publicmethods
+    *   @publicmethod ParseImports
+    *   @publicmethod ParseDeclarations
     *   @publicmethod Parse
     *   @publicmethod Print
     @brief A custom lexer for Squishy IDL.
 */
 type Parser struct {
-    myLexer *lexer.Lexer
-    Result  types.Scheme
+    myLexer        *lexer.Lexer
+    genericStructs map[string][]string
+    currentGeneric string
+    Result         types.Scheme
 }
 
 // Private Methods
@@ -89,23 +135,73 @@ func (parser *Parser) printStructFields(fields []*types.Field) {
     ui.Log(config.UPPERCLASS, "info", "Fields:")
 
     for index, field := range fields {
-        ui.Log(config.MIDCLASS, "info", fmt.Sprintf("%d'th Field", index+1))
+        if field.ID != 0 {
+            ui.Log(config.MIDCLASS, "info", fmt.Sprintf("%d'th Field, Id: %d", index+1, field.ID))
+        } else {
+            ui.Log(config.MIDCLASS, "info", fmt.Sprintf("%d'th Field", index+1))
+        }
 
         desc := parser.getFieldTypeDescription(field.Type)
+        if field.Default != "" {
+            desc += ", Default: " + field.Default
+        }
+        if field.Deprecated {
+            desc += ", Deprecated"
+        }
         ui.Log(config.BOTTOMCLASS, "info", desc)
     }
 }
 
 func (parser *Parser) getFieldTypeDescription(t *types.Type) string {
+    if t.IsOptional {
+        optional := *t
+        optional.IsOptional = false
+        return parser.getFieldTypeDescription(&optional) + ", Optional"
+    }
     if t.IsArray {
-        return fmt.Sprintf("Type: %s, Array, Dynamic: %t", t.Name, t.ArraySize != -1)
+        return fmt.Sprintf("Array, Dynamic: %t, Varint Length: %t, Of: (%s)", t.ArraySize <= 0, t.IsVarintLength, parser.getFieldTypeDescription(t.Element))
+    }
+    if t.IsSet {
+        return fmt.Sprintf("Set, Of: (%s)", parser.getFieldTypeDescription(t.Element))
+    }
+    if t.IsMap && t.Key != nil {
+        return fmt.Sprintf("Map, Key: (%s), Of: (%s)", parser.getFieldTypeDescription(t.Key), parser.getFieldTypeDescription(t.Element))
     }
     if t.IsMap {
-        return fmt.Sprintf("Type: %s, Map", t.Name)
+        return fmt.Sprintf("Map, Of: (%s)", parser.getFieldTypeDescription(t.Element))
+    }
+    if t.Quantization != nil {
+        q := t.Quantization
+        return fmt.Sprintf("Type: %s, Min: %g, Max: %g, Precision: %g, Bits: %d", t.Name, q.Min, q.Max, q.Precision, q.Bits)
+    }
+    if t.Range != nil {
+        return fmt.Sprintf("Type: %s, Range: %d..%d, Bits: %d", t.Name, t.Range.Min, t.Range.Max, t.Range.Bits)
+    }
+    if len(t.TypeArguments) > 0 {
+        arguments := make([]string, 0, len(t.TypeArguments))
+        for _, argument := range t.TypeArguments {
+            arguments = append(arguments, "("+parser.getFieldTypeDescription(argument)+")")
+        }
+        return fmt.Sprintf("Type: %s, Instance Of Generic Struct, Arguments: %s", t.Name, strings.Join(arguments, ", "))
+    }
+    if t.IsTypeParameter {
+        return fmt.Sprintf("Type: %s, Type Parameter", t.Name)
     }
     if t.IsReferenceToAnotherStruct {
         return fmt.Sprintf("Type: %s, Reference To Another Struct", t.Name)
     }
+    if t.IsReferenceToAnEnum {
+        return fmt.Sprintf("Type: %s, Reference To An Enum", t.Name)
+    }
+    if t.RobloxEnum != "" {
+        return fmt.Sprintf("Type: Enum.%s, Roblox Enum", t.RobloxEnum)
+    }
+    if t.InstanceClass != "" {
+        return fmt.Sprintf("Type: %s, Class: %s", t.Name, t.InstanceClass)
+    }
+    if t.IsReferenceToAUnion {
+        return fmt.Sprintf("Type: %s, Reference To A Union", t.Name)
+    }
     return fmt.Sprintf("Type: %s", t.Name)
 }
 
@@ -113,6 +209,14 @@ func (parser *Parser) printSingleStruct(s *types.Struct) {
     ui.Log(config.ROYAL, "info", "Struct "+s.Name+" At '"+s.Reference+"'")
     ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Field count: %d", len(s.Fields)))
     ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Ever Referenced: '%t'", s.EverReferenced))
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Packed: '%t'", s.Packed))
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Recursive: '%t'", s.Recursive))
+    if s.Base != "" {
+        ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Extends: '%s', Inherited field count: %d", s.Base, len(s.Inherited)))
+    }
+    if len(s.TypeParameters) > 0 {
+        ui.Log(config.UPPERCLASS, "info", "Type Parameters: "+strings.Join(s.TypeParameters, ", "))
+    }
 
     parser.printStructFields(s.Fields)
 
@@ -129,6 +233,179 @@ func (parser *Parser) printSingleStruct(s *types.Struct) {
             ui.Log(config.MIDCLASS, "info", fmt.Sprintf("Reference '%s', Times: %d", name, len(locations)))
         }
     }
+
+    if len(s.EnumReferences) > 0 {
+        ui.Log(config.UPPERCLASS, "info", "Enum References:")
+        for name, locations := range s.EnumReferences {
+            ui.Log(config.MIDCLASS, "info", fmt.Sprintf("Reference '%s', Times: %d", name, len(locations)))
+        }
+    }
+
+    if len(s.UnionReferences) > 0 {
+        ui.Log(config.UPPERCLASS, "info", "Union References:")
+        for name, locations := range s.UnionReferences {
+            ui.Log(config.MIDCLASS, "info", fmt.Sprintf("Reference '%s', Times: %d", name, len(locations)))
+        }
+    }
+}
+
+func (parser *Parser) printSingleEnum(e *types.Enum) {
+    ui.Log(config.ROYAL, "info", "Enum "+e.Name+" At '"+e.Reference+"'")
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Member count: %d", len(e.Members)))
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Ever Referenced: '%t'", e.EverReferenced))
+    ui.Log(config.UPPERCLASS, "info", "Members: "+strings.Join(e.Members, ", "))
+}
+
+func (parser *Parser) printSingleUnion(u *types.Union) {
+    ui.Log(config.ROYAL, "info", "Union "+u.Name+" At '"+u.Reference+"'")
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Arm count: %d", len(u.Arms)))
+    ui.Log(config.UPPERCLASS, "info", fmt.Sprintf("Ever Referenced: '%t'", u.EverReferenced))
+    ui.Log(config.UPPERCLASS, "info", "Arms:")
+
+    for index, arm := range u.Arms {
+        ui.Log(config.MIDCLASS, "info", fmt.Sprintf("%d'th Arm '%s'", index, arm.Name))
+        ui.Log(config.BOTTOMCLASS, "info", parser.getFieldTypeDescription(arm.Type))
+    }
+}
+
+func (parser *Parser) isAUnionName(name string) bool {
+    if _, found := parser.Result.Unions[name]; found {
+        return true
+    }
+
+    for _, tokenIndex := range parser.myLexer.UnionReferences {
+        if tokenIndex+1 < parser.myLexer.Length() && parser.myLexer.TokenList[tokenIndex+1].Value == name {
+            return true
+        }
+    }
+
+    return false
+}
+
+func (parser *Parser) findDeclaration(name string) (string, bool) {
+    if val, found := parser.Result.Structs[name]; found {
+        return val.Reference, true
+    }
+    if val, found := parser.Result.Enums[name]; found {
+        return val.Reference, true
+    }
+    if val, found := parser.Result.Unions[name]; found {
+        return val.Reference, true
+    }
+    if val, found := parser.Result.Aliases[name]; found {
+        return val.Reference, true
+    }
+
+    return "", false
+}
+
+// Structs parsed after current one are found through their tokens, like unions in isAUnionName.
+func (parser *Parser) isADeclaredName(name string) bool {
+    if _, found := parser.findDeclaration(name); found || parser.isAUnionName(name) {
+        return true
+    }
+
+    for _, tokenIndex := range parser.myLexer.StructReferences {
+        if tokenIndex+1 < parser.myLexer.Length() && parser.myLexer.TokenList[tokenIndex+1].Value == name {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Generic structs of imports are already parsed, ones of this file are collected before types are parsed.
+func (parser *Parser) getTypeParameters(name string) ([]string, bool) {
+    if parameters, found := parser.genericStructs[name]; found {
+        return parameters, true
+    }
+
+    if _struct, found := parser.Result.Structs[name]; found && len(_struct.TypeParameters) > 0 {
+        return _struct.TypeParameters, true
+    }
+
+    return nil, false
+}
+
+// Structs and unions can both reference structs and unions, so both are nodes of the reference graph.
+func (parser *Parser) getReferenceNode(name string) ([]*types.Field, map[string][]int, bool) {
+    references := make(map[string][]int)
+
+    if _struct, found := parser.Result.Structs[name]; found {
+        for k, v := range _struct.OtherStructReferences {
+            references[k] = v
+        }
+        for k, v := range _struct.UnionReferences {
+            references[k] = v
+        }
+
+        return _struct.Fields, references, true
+    }
+
+    if _union, found := parser.Result.Unions[name]; found {
+        for k, v := range _union.OtherStructReferences {
+            references[k] = v
+        }
+        for k, v := range _union.UnionReferences {
+            references[k] = v
+        }
+
+        return _union.Arms, references, true
+    }
+
+    return nil, nil, false
+}
+
+func (parser *Parser) markReferences(currentName string,
+    structReferences map[string][]int, enumReferences map[string][]int, unionReferences map[string][]int,
+) {
+    for referencedName := range structReferences {
+        if targetStruct, found := parser.Result.Structs[referencedName]; found {
+            targetStruct.EverReferenced = true
+            targetStruct.ReferencedBy[currentName]++
+        }
+    }
+
+    for referencedName := range enumReferences {
+        if targetEnum, found := parser.Result.Enums[referencedName]; found {
+            targetEnum.EverReferenced = true
+            targetEnum.ReferencedBy[currentName]++
+        }
+    }
+
+    for referencedName := range unionReferences {
+        if targetUnion, found := parser.Result.Unions[referencedName]; found {
+            targetUnion.EverReferenced = true
+            targetUnion.ReferencedBy[currentName]++
+        }
+    }
+}
+
+// Extending a struct only references it when the extending struct is sent, either exported or referenced itself.
+func (parser *Parser) markExtensions() {
+    marked := map[string]bool{}
+
+    for changed := true; changed; {
+        changed = false
+
+        for _, name := range slices.Sorted(maps.Keys(parser.Result.Structs)) {
+            _struct := parser.Result.Structs[name]
+
+            if _struct.Base == "" || marked[name] {
+                continue
+            }
+
+            if !_struct.EverReferenced && !slices.Contains(parser.Result.Exports, name) {
+                continue
+            }
+
+            base := parser.Result.Structs[_struct.Base]
+            base.EverReferenced = true
+            base.ReferencedBy[name]++
+            marked[name] = true
+            changed = true
+        }
+    }
 }
 
 func (parser *Parser) isTokenAValidType(token *types.Token) *errors.StackError {
@@ -142,190 +419,1085 @@ func (parser *Parser) isTokenAValidType(token *types.Token) *errors.StackError {
     return nil
 }
 
-func (parser *Parser) parseMap(_type *types.Type) *errors.StackError {
-    _type.IsMap = true
-    token1 := parser.myLexer.GetAtCursor()
-    nextToken := parser.myLexer.Next()
+func (parser *Parser) parseRangeBound(start *types.Token) (int, *errors.StackError) {
+    token := parser.myLexer.Next()
+    sign := 1
 
-    if nextToken.Value == "}" {
-        return errors.New(errors.NoTypeSpecifiedForMap, token1.RealPosition)
+    if token != nil && token.Value == "-" {
+        sign = -1
+        token = parser.myLexer.Next()
     }
 
-    if parser.myLexer.Next().Value != "}" {
-        return errors.New(errors.CurlyBraceNotClosed, token1.RealPosition)
+    if token == nil || token.Is != types.IntToken {
+        return 0, errors.New(errors.ExpectedRange, start.RealPosition, start.Value)
     }
 
-    nextNextToken := parser.myLexer.Next() // Oh god
+    bound, err := strconv.Atoi(token.Value)
+    if err != nil {
+        return 0, errors.New(errors.UnknownError, err.Error())
+    }
 
-    if nextNextToken.Value != "map" && nextNextToken.Value != "smap" {
-        return errors.New(errors.ExpectedMapDefinition, token1.RealPosition)
+    return sign * bound, nil
+}
+
+// Parses 'range min..max' after an integer type, 'range' can be omitted.
+func (parser *Parser) parseRange(_type *types.Type) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
+    front := parser.myLexer.LookAtFront()
+
+    if front != nil && front.Value == "range" {
+        token = parser.myLexer.Next()
+    } else if front == nil || (front.Is != types.IntToken && front.Value != "-") {
+        if _type.Name == "int" {
+            return errors.New(errors.RangeRequired, token.RealPosition)
+        }
+
+        return nil
+    }
+
+    bounds, isInteger := language.IntegerBounds[_type.Name]
+    if !isInteger {
+        return errors.New(errors.RangeOnlyForIntegers, _type.Name, token.RealPosition)
     }
 
-    if err := parser.isTokenAValidType(nextToken); err != nil {
+    min, err := parser.parseRangeBound(token)
+    if err != nil {
         return err
     }
 
-    _type.IsShortMap = nextNextToken.Value == "smap"
-    _type.Name = nextToken.Value
+    if separator := parser.myLexer.Next(); separator == nil || separator.Value != ".." {
+        return errors.New(errors.ExpectedRange, token.RealPosition, token.Value)
+    }
+
+    max, err2 := parser.parseRangeBound(token)
+    if err2 != nil {
+        return err2
+    }
+
+    if min > max {
+        return errors.New(errors.InvalidRange, min, max, token.RealPosition)
+    }
+
+    if min < bounds[0] || max > bounds[1] || max-min > language.MaxRangeSpan {
+        return errors.New(errors.RangeOutOfBounds, min, max, token.RealPosition, _type.Name)
+    }
+
+    bits := 1
+
+    for (1 << bits) <= max-min {
+        bits++
+    }
+
+    _type.Range = &types.Range{
+        Min:  min,
+        Max:  max,
+        Bits: bits,
+    }
 
     return nil
 }
 
-func (parser *Parser) parseArray(_type *types.Type) *errors.StackError {
-    _type.IsArray = true
-    token1 := parser.myLexer.GetAtCursor()
-    nextToken := parser.myLexer.Next()
+func (parser *Parser) parseNumber() (float64, bool) {
+    token := parser.myLexer.Next()
+    sign := 1.0
 
-    switch nextToken.Is {
-    case types.IntToken:
-        arraySize, err := strconv.Atoi(nextToken.Value)
+    if token != nil && token.Value == "-" {
+        sign = -1
+        token = parser.myLexer.Next()
+    }
 
-        if err != nil {
-            return errors.New(errors.UnknownError, err.Error())
-        }
+    if token == nil || (token.Is != types.IntToken && token.Is != types.FloatToken) {
+        return 0, false
+    }
 
-        _type.ArraySize = arraySize
+    number, err := strconv.ParseFloat(token.Value, 64)
+    if err != nil {
+        return 0, false
+    }
 
-        nextToken := parser.myLexer.Next()
-        if nextToken.Value != "]" {
-            return errors.New(errors.BracketNotClosed, token1.RealPosition, nextToken.Value, nextToken.RealPosition)
-        }
+    return sign * number, true
+}
 
-        nextToken = parser.myLexer.Next()
-        if err := parser.isTokenAValidType(nextToken); err != nil {
-            return err
-        }
-        _type.Name = nextToken.Value
-    default:
-        if nextToken.Value != "]" {
-            return errors.New(errors.BracketNotClosed, token1.RealPosition, nextToken.Value, nextToken.RealPosition)
+// Parses '(min, max, precision)' after a quantized type.
+func (parser *Parser) parseQuantization(_type *types.Type) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
+    parameters := [3]float64{}
+
+    if opening := parser.myLexer.Next(); opening == nil || opening.Value != "(" {
+        return errors.New(errors.ExpectedQuantization, _type.Name, token.RealPosition, _type.Name)
+    }
+
+    for i := range parameters {
+        number, isNumber := parser.parseNumber()
+        if !isNumber {
+            return errors.New(errors.ExpectedQuantization, _type.Name, token.RealPosition, _type.Name)
         }
-        nextToken = parser.myLexer.Next()
-        if err := parser.isTokenAValidType(nextToken); err != nil {
-            return err
+
+        parameters[i] = number
+
+        separator := parser.myLexer.Next()
+        if separator == nil || (i < 2 && separator.Value != ",") || (i == 2 && separator.Value != ")") {
+            return errors.New(errors.ExpectedQuantization, _type.Name, token.RealPosition, _type.Name)
         }
-        _type.Name = nextToken.Value
+    }
+
+    min, max, precision := parameters[0], parameters[1], parameters[2]
+
+    if min >= max || precision <= 0 || (max-min)/precision > float64(language.MaxRangeSpan) {
+        return errors.New(errors.InvalidQuantization, _type.Name, min, max, precision, token.RealPosition)
+    }
+
+    steps := int(math.Ceil((max - min) / precision))
+    bits := 1
+
+    for (1 << bits) <= steps {
+        bits++
+    }
+
+    _type.Quantization = &types.Quantization{
+        Min:       min,
+        Max:       max,
+        Precision: precision,
+        Bits:      bits,
     }
 
     return nil
 }
 
-func (parser *Parser) parseType() (types.Type, *errors.StackError) {
-    _type := types.Type{
-        Name:                       "",
-        IsArray:                    false,
-        ArraySize:                  -1,
-        IsMap:                      false,
-        IsShortMap:                 false,
-        IsReferenceToAnotherStruct: false,
-    }
+// Parses '<Family>' after enum, Roblox EnumItems are sent as their u16 Value.
+func (parser *Parser) parseRobloxEnum(_type *types.Type) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
 
-    token1 := parser.myLexer.GetAtCursor()
+    opening := parser.myLexer.Next()
+    family := parser.myLexer.Next()
+    closing := parser.myLexer.Next()
 
-    switch token1.Value {
-    case "{": // Map
-        if err := parser.parseMap(&_type); err != nil {
-            return _type, err
-        }
-    case "[": // Array
-        if err := parser.parseArray(&_type); err != nil {
-            return _type, err
-        }
-    default: // Normal Type
-        if err := parser.isTokenAValidType(token1); err != nil {
-            return _type, err
-        }
-        _type.Name = token1.Value
+    if opening == nil || opening.Value != "<" || family == nil || closing == nil || closing.Value != ">" {
+        return errors.New(errors.ExpectedRobloxEnumFamily, token.RealPosition)
     }
 
-    if language.DefaultTypes[_type.Name] != true {
-        _type.IsReferenceToAnotherStruct = true
+    if !language.RobloxEnums[family.Value] {
+        return errors.New(errors.UnknownRobloxEnum, family.Value, family.RealPosition)
     }
 
-    return _type, nil
+    _type.Name = token.Value
+    _type.RobloxEnum = family.Value
+
+    return nil
 }
 
-func (parser *Parser) parseField(at int) (int, types.Field, *errors.StackError) {
-    field := types.Field{
-        Name: "",
-        Type: &types.Type{
-            Name:                       "",
-            IsArray:                    false,
-            ArraySize:                  -1,
-            IsMap:                      false,
-            IsReferenceToAnotherStruct: false,
-        },
+// Parses optional '<Class>' after instance.
+func (parser *Parser) parseInstanceClass(_type *types.Type) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
+
+    if front := parser.myLexer.LookAtFront(); front == nil || front.Value != "<" {
+        return nil
     }
 
-    parser.myLexer.JumpCursorAhead(at)
+    parser.myLexer.StepCursorForward(1)
+
+    class := parser.myLexer.Next()
+    closing := parser.myLexer.Next()
+
+    if class == nil || closing == nil || closing.Value != ">" {
+        return errors.New(errors.ExpectedInstanceClass, token.RealPosition)
+    }
+    if _, err := util.IsAValidName(class.Value); err != nil {
+        return errors.New(errors.ExpectedInstanceClass, token.RealPosition)
+    }
+
+    _type.InstanceClass = class.Value
+
+    return nil
+}
+
+func (parser *Parser) parseElementType(_type *types.Type) *errors.StackError {
     token := parser.myLexer.GetAtCursor()
 
-    name := parser.myLexer.LookAtFront()
-    if _, err := util.IsAValidName(name.Value); err != nil {
-        return 0, types.Field{}, err
+    element, err := parser.parseType()
+    if err != nil {
+        return err
     }
-    if name.Is != types.FieldNameToken {
-        return 0, types.Field{}, errors.New(errors.ExpectedNameForField, token.RealPosition)
+
+    if element.IsOptional {
+        return errors.New(errors.ElementCantBeOptional, token.RealPosition)
     }
 
-    parser.myLexer.StepCursorForward(2)
-    _type, err := parser.parseType()
+    _type.Element = &element
+    _type.Name = element.Name
+
+    return nil
+}
+
+func (parser *Parser) parseMapKey(_type *types.Type) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
+
+    key, err := parser.parseType()
     if err != nil {
-        return parser.myLexer.Cursor, field, err
+        return err
     }
 
-    field.Type = &_type
-    field.Name = name.Value
+    if key.IsOptional || key.IsArray || key.IsMap || !(language.DefaultTypes[key.Name] || key.IsReferenceToAnEnum || key.RobloxEnum != "") {
+        return errors.New(errors.InvalidMapKeyType, token.Value, token.RealPosition)
+    }
 
-    return parser.myLexer.Cursor, field, nil
+    _type.Key = &key
+
+    parser.myLexer.StepCursorForward(2) // Skip ':'
+
+    return nil
+}
+
+// Returns how many tokens the type name at cursor spans, 'enum<Material>' spans 4.
+func (parser *Parser) getTypeNameLength() int {
+    if front := parser.myLexer.LookAtFront(); front == nil || front.Value != "<" {
+        return 1
+    }
+
+    depth := 0
+
+    for i := 1; ; i++ {
+        token := parser.myLexer.LookAhead(i)
+        if token == nil {
+            return i
+        }
+
+        switch token.Value {
+        case "<":
+            depth++
+        case ">":
+            depth--
+
+            if depth == 0 {
+                return i + 1
+            }
+        }
+    }
 }
 
-func (parser *Parser) parseFields(_struct *types.Struct, start int) *errors.StackError {
-    fieldNames := map[string]bool{}
+func (parser *Parser) parseMap(_type *types.Type) *errors.StackError {
+    _type.IsMap = true
+    token1 := parser.myLexer.GetAtCursor()
+    nextToken := parser.myLexer.Next()
+
+    if nextToken.Value == "}" {
+        return errors.New(errors.NoTypeSpecifiedForMap, token1.RealPosition)
+    }
+
+    if front := parser.myLexer.LookAhead(parser.getTypeNameLength()); front != nil && front.Value == ":" { // Typed key
+        if err := parser.parseMapKey(_type); err != nil {
+            return err
+        }
+    }
+
+    if err := parser.parseElementType(_type); err != nil {
+        return err
+    }
+
+    if parser.myLexer.Next().Value != "}" {
+        return errors.New(errors.CurlyBraceNotClosed, token1.RealPosition)
+    }
+
+    nextNextToken := parser.myLexer.Next() // Oh god
+
+    if nextNextToken.Value == "set" { // Elements are keys of the set, so they follow rules of map keys.
+        element := _type.Element
+        elementName := element.Name
+
+        if element.IsArray {
+            elementName = "array"
+        } else if element.IsMap {
+            elementName = "map"
+        }
+
+        if _type.Key != nil || element.IsArray || element.IsMap || !(language.DefaultTypes[element.Name] || element.IsReferenceToAnEnum || element.RobloxEnum != "") {
+            return errors.New(errors.InvalidSetElementType, elementName, token1.RealPosition)
+        }
+
+        _type.IsSet = true
+
+        return nil
+    }
+
+    if nextNextToken.Value != "map" && nextNextToken.Value != "smap" && nextNextToken.Value != "vmap" {
+        return errors.New(errors.ExpectedMapDefinition, token1.RealPosition)
+    }
+
+    _type.IsShortMap = nextNextToken.Value == "smap"
+    _type.IsVarintLength = nextNextToken.Value == "vmap"
+
+    return nil
+}
+
+func (parser *Parser) parseArray(_type *types.Type) *errors.StackError {
+    _type.IsArray = true
+    token1 := parser.myLexer.GetAtCursor()
+    nextToken := parser.myLexer.Next()
+    sizeToken := nextToken
+    hasSize := false
+
+    switch nextToken.Is {
+    case types.IntToken:
+        arraySize, err := strconv.Atoi(nextToken.Value)
+
+        if err != nil {
+            return errors.New(errors.UnknownError, err.Error())
+        }
+
+        _type.ArraySize = arraySize
+        hasSize = true
+        nextToken = parser.myLexer.Next()
+    case types.TypeToken: // Varint length or constant
+        if nextToken.Value == "vu32" {
+            _type.IsVarintLength = true
+            nextToken = parser.myLexer.Next()
+            break
+        }
+
+        constant, found := parser.Result.Constants[nextToken.Value]
+
+        if !found {
+            return errors.New(errors.UnknownConstant, nextToken.Value, nextToken.RealPosition)
+        }
+
+        _type.ArraySize = constant.Value
+        hasSize = true
+        nextToken = parser.myLexer.Next()
+    }
+
+    // Backend sends arrays without a positive size as dynamic ones.
+    if hasSize && _type.ArraySize <= 0 {
+        return errors.New(errors.InvalidArraySize, sizeToken.Value, sizeToken.RealPosition, _type.ArraySize)
+    }
+
+    if nextToken.Value != "]" {
+        return errors.New(errors.BracketNotClosed, token1.RealPosition, nextToken.Value, nextToken.RealPosition)
+    }
+
+    parser.myLexer.Next()
+
+    // Element can be another array or map.
+    return parser.parseElementType(_type)
+}
+
+// Parses '<Type, Type>' after name of a generic struct, there must be an argument for every type parameter.
+func (parser *Parser) parseTypeArguments(_type *types.Type, parameters []string) *errors.StackError {
+    token := parser.myLexer.GetAtCursor()
+
+    if front := parser.myLexer.LookAtFront(); front == nil || front.Value != "<" {
+        return errors.New(errors.TypeArgumentCountMismatch, token.Value, len(parameters), 0, token.RealPosition)
+    }
+
+    parser.myLexer.StepCursorForward(2)
+
+    for {
+        argument, err := parser.parseType()
+        if err != nil {
+            return err
+        }
+
+        _type.TypeArguments = append(_type.TypeArguments, &argument)
+
+        separator := parser.myLexer.Next()
+        if separator == nil {
+            return errors.New(errors.TypeArgumentCountMismatch, token.Value, len(parameters), len(_type.TypeArguments), token.RealPosition)
+        }
+        if separator.Value == ">" {
+            break
+        }
+        if separator.Value != "," {
+            return errors.New(errors.ExpectedAValidType, separator.RealPosition, separator.Value)
+        }
+
+        parser.myLexer.StepCursorForward(1)
+    }
+
+    if len(_type.TypeArguments) != len(parameters) {
+        return errors.New(errors.TypeArgumentCountMismatch, token.Value, len(parameters), len(_type.TypeArguments), token.RealPosition)
+    }
+
+    return nil
+}
+
+func (parser *Parser) parseType() (types.Type, *errors.StackError) {
+    _type := types.Type{
+        Name:                       "",
+        IsArray:                    false,
+        ArraySize:                  -1,
+        IsMap:                      false,
+        IsShortMap:                 false,
+        IsReferenceToAnotherStruct: false,
+        IsOptional:                 false,
+    }
+
+    token1 := parser.myLexer.GetAtCursor()
+
+    if token1.Value == "?" { // Optional
+        _type.IsOptional = true
+        token1 = parser.myLexer.Next()
+    }
+
+    switch token1.Value {
+    case "{": // Map
+        if err := parser.parseMap(&_type); err != nil {
+            return _type, err
+        }
+    case "[": // Array
+        if err := parser.parseArray(&_type); err != nil {
+            return _type, err
+        }
+    case "enum": // Roblox EnumItem
+        if err := parser.parseRobloxEnum(&_type); err != nil {
+            return _type, err
+        }
+    default: // Normal Type
+        if err := parser.isTokenAValidType(token1); err != nil {
+            return _type, err
+        }
+
+        // Aliases are replaced with the type they stand for, name is kept only for Luau output.
+        if alias, isAlias := parser.Result.Aliases[token1.Value]; isAlias {
+            resolved := *alias.Type
+            resolved.AliasName = alias.Name
+            resolved.IsOptional = resolved.IsOptional || _type.IsOptional
+            alias.EverReferenced = true
+
+            return resolved, nil
+        }
+
+        // Type parameters are replaced with arguments of each instance by middleend.
+        if parser.currentGeneric != "" && slices.Contains(parser.genericStructs[parser.currentGeneric], token1.Value) {
+            _type.Name = token1.Value
+            _type.IsTypeParameter = true
+
+            return _type, nil
+        }
+
+        if parser.currentGeneric != "" && !language.DefaultTypes[token1.Value] && !parser.isADeclaredName(token1.Value) {
+            return _type, errors.New(errors.UnboundTypeParameter, token1.Value, token1.RealPosition, parser.currentGeneric)
+        }
+
+        _type.Name = token1.Value
+
+        if parameters, isGeneric := parser.getTypeParameters(_type.Name); isGeneric {
+            if err := parser.parseTypeArguments(&_type, parameters); err != nil {
+                return _type, err
+            }
+        } else if front := parser.myLexer.LookAtFront(); front != nil && front.Value == "<" && _type.Name != "instance" {
+            return _type, errors.New(errors.NotAGenericStruct, _type.Name, token1.RealPosition)
+        } else if language.QuantizedTypes[_type.Name] {
+            if err := parser.parseQuantization(&_type); err != nil {
+                return _type, err
+            }
+        } else if _type.Name == "instance" {
+            if err := parser.parseInstanceClass(&_type); err != nil {
+                return _type, err
+            }
+        } else if err := parser.parseRange(&_type); err != nil {
+            return _type, err
+        }
+    }
+
+    if language.DefaultTypes[_type.Name] != true && _type.RobloxEnum == "" {
+        if _, isEnum := parser.Result.Enums[_type.Name]; isEnum {
+            _type.IsReferenceToAnEnum = true
+        } else if parser.isAUnionName(_type.Name) {
+            _type.IsReferenceToAUnion = true
+        } else {
+            _type.IsReferenceToAnotherStruct = true
+        }
+    }
+
+    return _type, nil
+}
+
+// Whole number types only take whole numbers, ranges and quantizations bound the default too.
+func doesNumberFitType(number float64, _type *types.Type) bool {
+    if language.IntegerTypes[_type.Name] {
+        if number != math.Trunc(number) {
+            return false
+        }
+
+        if (strings.HasPrefix(_type.Name, "u") || strings.HasPrefix(_type.Name, "vu")) && number < 0 {
+            return false
+        }
+    }
+
+    if _type.Range != nil {
+        return number >= float64(_type.Range.Min) && number <= float64(_type.Range.Max)
+    }
+
+    if bounds, isBounded := language.IntegerBounds[_type.Name]; isBounded {
+        return number >= float64(bounds[0]) && number <= float64(bounds[1])
+    }
+
+    if _type.Quantization != nil {
+        return number >= _type.Quantization.Min && number <= _type.Quantization.Max
+    }
+
+    return true
+}
+
+func (parser *Parser) parseStringDefault(name string, typeName string, at *types.Token, members []string) (string, *errors.StackError) {
+    token := parser.myLexer.Next()
+
+    value, err := strconv.Unquote(token.Value)
+    if token.Is != types.StringToken || err != nil {
+        return "", errors.New(errors.InvalidDefaultValue, token.Value, name, at.RealPosition, typeName)
+    }
+
+    if members != nil && !slices.Contains(members, value) {
+        return "", errors.New(errors.InvalidDefaultValue, token.Value, name, at.RealPosition, typeName)
+    }
+
+    return strconv.Quote(value), nil
+}
+
+// Parses literal after '=' of a field and returns it as Luau source.
+func (parser *Parser) parseDefault(name string, _type *types.Type, at *types.Token) (string, *errors.StackError) {
+    typeName := _type.Name
+    if _type.AliasName != "" {
+        typeName = _type.AliasName
+    } else if _type.IsArray {
+        typeName = "array"
+    } else if _type.IsSet {
+        typeName = "set"
+    } else if _type.IsMap {
+        typeName = "map"
+    } else if _type.RobloxEnum != "" {
+        typeName = "enum<" + _type.RobloxEnum + ">"
+    }
+
+    if _type.IsArray || _type.IsMap || _type.IsReferenceToAnotherStruct || _type.IsReferenceToAUnion {
+        return "", errors.New(errors.DefaultNotSupportedForType, name, at.RealPosition, typeName)
+    }
+
+    front := parser.myLexer.LookAtFront()
+    if front == nil || front.Is == types.KeywordToken || (front.Is == types.OperatorToken && front.Value != "-") {
+        return "", errors.New(errors.ExpectedDefaultValue, name, at.RealPosition)
+    }
+
+    if _type.IsReferenceToAnEnum {
+        return parser.parseStringDefault(name, typeName, at, parser.Result.Enums[_type.Name].Members)
+    }
+
+    if _type.RobloxEnum != "" {
+        token := parser.myLexer.Next()
+
+        item, err := strconv.Unquote(token.Value)
+        if token.Is != types.StringToken || err != nil {
+            return "", errors.New(errors.InvalidDefaultValue, token.Value, name, at.RealPosition, typeName)
+        }
+        if _, err := util.IsAValidName(item); err != nil {
+            return "", errors.New(errors.InvalidDefaultValue, token.Value, name, at.RealPosition, typeName)
+        }
+
+        return fmt.Sprintf("Enum.%s.%s", _type.RobloxEnum, item), nil
+    }
+
+    switch language.DefaultTypesToRobloxTypes[_type.Name] {
+    case "number":
+        number, isNumber := parser.parseNumber()
+        if !isNumber {
+            return "", errors.New(errors.InvalidDefaultValue, parser.myLexer.GetAtCursor().Value, name, at.RealPosition, typeName)
+        }
+
+        literal := strconv.FormatFloat(number, 'f', -1, 64)
+        if !doesNumberFitType(number, _type) {
+            return "", errors.New(errors.InvalidDefaultValue, literal, name, at.RealPosition, typeName)
+        }
+
+        return literal, nil
+    case "boolean":
+        token := parser.myLexer.Next()
+        if token.Value != "true" && token.Value != "false" {
+            return "", errors.New(errors.InvalidDefaultValue, token.Value, name, at.RealPosition, typeName)
+        }
+
+        return token.Value, nil
+    case "string":
+        return parser.parseStringDefault(name, typeName, at, nil)
+    }
+
+    return "", errors.New(errors.DefaultNotSupportedForType, name, at.RealPosition, typeName)
+}
+
+func (parser *Parser) parseField(at int) (int, types.Field, *errors.StackError) {
+    field := types.Field{
+        Name: "",
+        Type: &types.Type{
+            Name:                       "",
+            IsArray:                    false,
+            ArraySize:                  -1,
+            IsMap:                      false,
+            IsReferenceToAnotherStruct: false,
+        },
+    }
+
+    parser.myLexer.JumpCursorAhead(at)
+    token := parser.myLexer.GetAtCursor()
+
+    if id := parser.myLexer.LookAtFront(); id != nil && id.Is == types.IntToken { // Stable field id
+        fieldId, err := strconv.Atoi(id.Value)
+        if err != nil || fieldId <= 0 {
+            return 0, types.Field{}, errors.New(errors.InvalidFieldId, id.Value, id.RealPosition)
+        }
+
+        field.ID = fieldId
+        parser.myLexer.StepCursorForward(1)
+    }
+
+    name := parser.myLexer.LookAtFront()
+    if _, err := util.IsAValidName(name.Value); err != nil {
+        return 0, types.Field{}, err
+    }
+    if name.Is != types.FieldNameToken {
+        return 0, types.Field{}, errors.New(errors.ExpectedNameForField, token.RealPosition)
+    }
+
+    parser.myLexer.StepCursorForward(2)
+    _type, err := parser.parseType()
+    if err != nil {
+        return parser.myLexer.Cursor, field, err
+    }
+
+    field.Type = &_type
+    field.Name = name.Value
+    field.Reference = name.RealPosition
+
+    if front := parser.myLexer.LookAtFront(); front != nil && front.Value == "=" {
+        parser.myLexer.StepCursorForward(1)
+
+        literal, err := parser.parseDefault(field.Name, field.Type, token)
+        if err != nil {
+            return parser.myLexer.Cursor, field, err
+        }
+
+        field.Default = literal
+    }
+
+    if front := parser.myLexer.LookAtFront(); front != nil && front.Value == "deprecated" {
+        field.Deprecated = true
+        parser.myLexer.StepCursorForward(1)
+    }
+
+    return parser.myLexer.Cursor, field, nil
+}
+
+// Field ids are optional, but if a struct uses them every field needs one.
+func (parser *Parser) validateFieldIds(_struct *types.Struct) *errors.StackError {
+    if len(_struct.Fields) == 0 || _struct.Fields[0].ID == 0 {
+        for _, field := range _struct.Fields {
+            if field.ID != 0 {
+                return errors.New(errors.FieldIdsMustBeSetForAllFields, _struct.Name, _struct.Fields[0].Name)
+            }
+        }
+
+        return nil
+    }
+
+    if _struct.Packed {
+        return errors.New(errors.PackedStructCantHaveFieldIds, _struct.Name)
+    }
+
+    fieldsById := map[int]*types.Field{}
+
+    for i, field := range _struct.Fields {
+        if field.ID == 0 {
+            return errors.New(errors.FieldIdsMustBeSetForAllFields, _struct.Name, field.Name)
+        }
+
+        if other, found := fieldsById[field.ID]; found {
+            return errors.New(errors.AnotherFieldWithSameIdExists, other.Name, field.Name, _struct.Name, field.ID)
+        }
+
+        if i > 0 && field.ID < _struct.Fields[i-1].ID {
+            return errors.New(errors.FieldIdsMustIncrease, field.Name, _struct.Name, field.ID, _struct.Fields[i-1].ID)
+        }
+
+        fieldsById[field.ID] = field
+    }
+
+    return nil
+}
+
+func (parser *Parser) parseReserved(_struct *types.Struct) *errors.StackError {
+    reserved := parser.myLexer.Next()
+
+    for {
+        token := parser.myLexer.Next()
+        if token == nil {
+            return errors.New(errors.ExpectedReservedValue, reserved.RealPosition, "")
+        }
+
+        switch token.Is {
+        case types.IntToken:
+            id, err := strconv.Atoi(token.Value)
+            if err != nil {
+                return errors.New(errors.UnknownError, err.Error())
+            }
+
+            _struct.ReservedIds[id] = true
+        case types.StringToken:
+            name, err := strconv.Unquote(token.Value)
+            if err != nil {
+                return errors.New(errors.ExpectedReservedValue, reserved.RealPosition, token.Value)
+            }
+
+            _struct.ReservedNames[name] = true
+        default:
+            return errors.New(errors.ExpectedReservedValue, reserved.RealPosition, token.Value)
+        }
+
+        if front := parser.myLexer.LookAtFront(); front == nil || front.Value != "," {
+            return nil
+        }
+
+        parser.myLexer.StepCursorForward(1)
+    }
+}
+
+// Reserved statements can be anywhere in struct, so reserved ids and names are checked after all fields are parsed.
+func (parser *Parser) checkReserved(_struct *types.Struct) *errors.StackError {
+    for _, field := range _struct.Fields {
+        if field.ID != 0 && _struct.ReservedIds[field.ID] {
+            return errors.New(errors.FieldUsesReservedId, field.Name, _struct.Name, field.ID)
+        }
+
+        if _struct.ReservedNames[field.Name] {
+            return errors.New(errors.FieldUsesReservedName, field.Name, _struct.Name)
+        }
+    }
+
+    return nil
+}
+
+func (parser *Parser) parseFields(_struct *types.Struct) *errors.StackError {
+    fieldNames := map[string]bool{}
+    var lastField *types.Field = nil
+
+    for front := parser.myLexer.LookAtFront(); front == nil || front.Value != "}"; front = parser.myLexer.LookAtFront() {
+        if front == nil {
+            return errors.New(errors.CurlyBraceNotClosed, _struct.Reference)
+        }
+
+        switch front.Value {
+        case "field":
+            endedAt, field, err := parser.parseField(front.Position)
+            if err != nil {
+                return err
+            }
+
+            if fieldNames[field.Name] {
+                return errors.New(errors.AnotherFieldWithSameNameExists, _struct.Name, field.Name)
+            }
+
+            fieldNames[field.Name] = true
+
+            noteReference(&field, len(_struct.Fields), _struct.OtherStructReferences, _struct.EnumReferences, _struct.UnionReferences)
+
+            _struct.Fields = append(_struct.Fields, &field)
+            lastField = &field
+
+            parser.myLexer.JumpCursorAhead(endedAt)
+        case "reserved":
+            if err := parser.parseReserved(_struct); err != nil {
+                return err
+            }
+        default:
+            if lastField != nil {
+                return errors.New(errors.ExpectedFieldAfterAnotherField, lastField.Name, front.RealPosition, _struct.Name)
+            }
+
+            return errors.New(errors.UnexpectedTokenAfterField, front.Value, front.RealPosition)
+        }
+    }
+
+    return parser.checkReserved(_struct)
+}
+
+func (parser *Parser) parseConstants() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.ConstantReferences {
+        parser.myLexer.JumpCursorAhead(tokenIndex)
+        token := parser.myLexer.GetAtCursor()
+
+        name := parser.myLexer.LookAtFront()
+        if name == nil || name.Is != types.ConstantNameToken {
+            return errors.New(errors.ExpectedNameForConstant, token.RealPosition)
+        }
+        if _, err := util.IsAValidName(name.Value); err != nil {
+            return err
+        }
+
+        operator := parser.myLexer.LookAhead(2)
+        value := parser.myLexer.LookAhead(3)
+        if operator == nil || operator.Value != "=" || value == nil || value.Is != types.IntToken {
+            return errors.New(errors.ExpectedValueForConstant, name.Value, token.RealPosition)
+        }
+
+        constantValue, err := strconv.Atoi(value.Value)
+        if err != nil {
+            return errors.New(errors.UnknownError, err.Error())
+        }
+
+        if val, found := parser.Result.Constants[name.Value]; found {
+            return errors.New(errors.AnotherConstantWithSameNameExists, name.Value, val.Reference, token.RealPosition)
+        }
+
+        parser.Result.Constants[name.Value] = &types.Constant{
+            Reference: token.RealPosition,
+            Name:      name.Value,
+            Value:     constantValue,
+        }
+
+        parser.myLexer.StepCursorForward(3)
+
+        nextToken := parser.myLexer.LookAtFront()
+        if nextToken != nil && !language.DeclarationKeywords[nextToken.Value] {
+            return errors.New(errors.UnexpectedTokenAfterConstant, nextToken.Value, nextToken.RealPosition)
+        }
+    }
+
+    return nil
+}
+
+// Targets are checked where alias is declared, an unused alias with an unknown target is still an error.
+func (parser *Parser) checkAliasTarget(_type *types.Type, alias string, position string) *errors.StackError {
+    if _type.Element != nil {
+        if err := parser.checkAliasTarget(_type.Element, alias, position); err != nil {
+            return err
+        }
+    }
+
+    if _type.Key != nil {
+        if err := parser.checkAliasTarget(_type.Key, alias, position); err != nil {
+            return err
+        }
+    }
+
+    for _, argument := range _type.TypeArguments {
+        if err := parser.checkAliasTarget(argument, alias, position); err != nil {
+            return err
+        }
+    }
+
+    if _type.IsArray || _type.IsMap || _type.RobloxEnum != "" || language.DefaultTypes[_type.Name] {
+        return nil
+    }
+
+    if !parser.isADeclaredName(_type.Name) {
+        return errors.New(errors.UnknownAliasTarget, _type.Name, alias, position)
+    }
+
+    return nil
+}
+
+// Aliases can only use aliases declared before them, so they can never be cyclic.
+func (parser *Parser) parseTypeAliases() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.TypeAliasReferences {
+        parser.myLexer.JumpCursorAhead(tokenIndex)
+        token := parser.myLexer.GetAtCursor()
+
+        name := parser.myLexer.LookAtFront()
+        if name == nil || name.Is != types.TypeAliasNameToken {
+            return errors.New(errors.ExpectedNameForTypeAlias, token.RealPosition)
+        }
+        if _, err := util.IsAValidName(name.Value); err != nil {
+            return err
+        }
+
+        if language.DefaultTypes[name.Value] == true {
+            return errors.New(errors.InvalidTypeAliasNaming, token.RealPosition, name.Value)
+        }
+
+        if reference, found := parser.findDeclaration(name.Value); found {
+            return errors.New(errors.AnotherDeclarationWithSameNameExists, name.Value, reference, token.RealPosition)
+        }
+
+        parser.myLexer.StepCursorForward(2)
+
+        if tok := parser.myLexer.GetAtCursor(); tok.Value != "=" {
+            return errors.New(errors.ExpectedEqualsForTypeAlias, name.Value, token.RealPosition, tok.Value)
+        }
+
+        parser.myLexer.StepCursorForward(1)
+
+        _type, err := parser.parseType()
+        if err != nil {
+            return err
+        }
+
+        if err := parser.checkAliasTarget(&_type, name.Value, token.RealPosition); err != nil {
+            return err
+        }
+
+        parser.Result.Aliases[name.Value] = &types.TypeAlias{
+            Reference:      token.RealPosition,
+            Name:           name.Value,
+            Type:           &_type,
+            EverReferenced: false,
+        }
+
+        nextToken := parser.myLexer.LookAtFront()
+        if nextToken != nil && !language.DeclarationKeywords[nextToken.Value] {
+            return errors.New(errors.UnexpectedTokenAfterTypeAlias, nextToken.Value, nextToken.RealPosition)
+        }
+    }
+
+    return nil
+}
+
+func (parser *Parser) parseEnumMembers(_enum *types.Enum) *errors.StackError {
+    memberNames := map[string]bool{}
+
+    for token := parser.myLexer.Next(); token.Value != "}"; token = parser.myLexer.Next() {
+        if token.Is == types.InvalidToken {
+            return errors.New(errors.CurlyBraceNotClosed, _enum.Reference)
+        }
+        if token.Is != types.TypeToken {
+            return errors.New(errors.UnexpectedTokenInEnum, token.Value, token.RealPosition, _enum.Name)
+        }
+        if _, err := util.IsAValidName(token.Value); err != nil {
+            return err
+        }
+
+        if memberNames[token.Value] {
+            return errors.New(errors.AnotherEnumMemberWithSameNameExists, _enum.Name, token.Value)
+        }
+
+        memberNames[token.Value] = true
+        _enum.Members = append(_enum.Members, token.Value)
+    }
+
+    return nil
+}
+
+func (parser *Parser) parseEnums() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.EnumReferences {
+        parser.myLexer.JumpCursorAhead(tokenIndex)
+        token := parser.myLexer.GetAtCursor()
+
+        name := parser.myLexer.LookAtFront()
+        if name == nil {
+            return errors.New(errors.ExpectedNameForEnum, token.RealPosition)
+        }
+        if _, err := util.IsAValidName(name.Value); err != nil {
+            return err
+        }
+        if name.Is != types.EnumNameToken {
+            return errors.New(errors.ExpectedNameForEnum, token.RealPosition)
+        }
+
+        if language.DefaultTypes[name.Value] == true {
+            return errors.New(errors.InvalidEnumNaming, token.RealPosition, name.Value)
+        }
+
+        parser.myLexer.StepCursorForward(2)
+
+        if tok := parser.myLexer.GetAtCursor(); tok.Value != "{" {
+            return errors.New(errors.EnumShouldStartWithCurlyBrace, token.RealPosition, tok.Value)
+        }
+
+        _enum := types.Enum{
+            Reference:      token.RealPosition,
+            Name:           name.Value,
+            Members:        []string{},
+            EverReferenced: false,
+            ReferencedBy:   make(map[string]int),
+        }
+
+        if err := parser.parseEnumMembers(&_enum); err != nil {
+            return err
+        }
 
-    for _, tokenIndex := range parser.myLexer.FieldReferences {
-        if tokenIndex < start {
-            continue
+        if reference, found := parser.findDeclaration(_enum.Name); found {
+            return errors.New(errors.AnotherDeclarationWithSameNameExists, _enum.Name, reference, _enum.Reference)
         }
 
-        for k := parser.myLexer.Cursor + 1; k < tokenIndex; k++ {
-            token := parser.myLexer.TokenList[k]
-            if token.Is != types.CommentToken {
-                return errors.New(errors.UnexpectedTokenAfterField, token.Value, token.RealPosition)
-            }
+        if len(_enum.Members) == 0 {
+            return errors.New(errors.AnEnumMustHaveAtleast1Member, _enum.Name)
         }
 
-        endedAt, field, err := parser.parseField(tokenIndex)
-        if err != nil {
-            return err
+        if len(_enum.Members) > language.MaxEnumMembers {
+            return errors.New(errors.TooManyEnumMembers, _enum.Name, len(_enum.Members), language.MaxEnumMembers)
         }
 
-        if fieldNames[field.Name] {
-            return errors.New(errors.AnotherFieldWithSameNameExists, _struct.Name, field.Name)
+        parser.Result.Enums[_enum.Name] = &_enum
+
+        nextToken := parser.myLexer.LookAtFront()
+        if nextToken != nil && !language.DeclarationKeywords[nextToken.Value] {
+            return errors.New(errors.UnexpectedTokenAfterEnum, nextToken.Value, nextToken.RealPosition)
         }
+    }
+
+    return nil
+}
 
-        fieldNames[field.Name] = true
+// Parses '<T, E>' after a struct name, cursor is left at '>'.
+func (parser *Parser) parseTypeParameters(name *types.Token) ([]string, *errors.StackError) {
+    parameters := []string{}
 
-        if field.Type.IsReferenceToAnotherStruct {
-            _struct.OtherStructReferences[field.Type.Name] = append(_struct.OtherStructReferences[field.Type.Name], len(_struct.Fields))
+    for {
+        token := parser.myLexer.Next()
+        if token == nil {
+            return nil, errors.New(errors.ExpectedTypeParameter, name.Value, name.RealPosition, "")
+        }
+        if token.Is != types.TypeToken || language.DefaultTypes[token.Value] {
+            return nil, errors.New(errors.ExpectedTypeParameter, name.Value, token.RealPosition, token.Value)
+        }
+        if _, err := util.IsAValidName(token.Value); err != nil {
+            return nil, err
+        }
+        if slices.Contains(parameters, token.Value) {
+            return nil, errors.New(errors.AnotherTypeParameterWithSameNameExists, name.Value, token.RealPosition, token.Value)
         }
 
-        _struct.Fields = append(_struct.Fields, &field)
+        parameters = append(parameters, token.Value)
 
-        parser.myLexer.JumpCursorAhead(endedAt)
-        if parser.myLexer.LookAtFront().Value == "}" {
-            return nil
+        separator := parser.myLexer.Next()
+        if separator == nil || (separator.Value != "," && separator.Value != ">") {
+            return nil, errors.New(errors.ExpectedTypeParameter, name.Value, token.RealPosition, token.Value)
+        }
+        if separator.Value == ">" {
+            return parameters, nil
+        }
+    }
+}
+
+func (parser *Parser) collectGenericStructs() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.StructReferences {
+        if tokenIndex+2 >= parser.myLexer.Length() || parser.myLexer.TokenList[tokenIndex+2].Value != "<" {
+            continue
         }
 
-        if parser.myLexer.LookAtFront().Value != "field" {
-            return errors.New(errors.ExpectedFieldAfterAnotherField, field.Name, parser.myLexer.LookAtFront().RealPosition, _struct.Name)
+        parser.myLexer.JumpCursorAhead(tokenIndex + 2)
+
+        name := &parser.myLexer.TokenList[tokenIndex+1]
+        parameters, err := parser.parseTypeParameters(name)
+        if err != nil {
+            return err
         }
+
+        parser.genericStructs[name.Value] = parameters
     }
 
     return nil
 }
 
 func (parser *Parser) parseStructs() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.PackedReferences {
+        modifier := parser.myLexer.TokenList[tokenIndex]
+        next := ""
+
+        if tokenIndex+1 < parser.myLexer.Length() {
+            next = parser.myLexer.TokenList[tokenIndex+1].Value
+        }
+
+        if next != "struct" {
+            return errors.New(errors.ExpectedStructAfterPacked, modifier.RealPosition, next)
+        }
+    }
+
     for _, tokenIndex := range parser.myLexer.StructReferences {
         parser.myLexer.JumpCursorAhead(tokenIndex)
         token := parser.myLexer.GetAtCursor()
@@ -343,6 +1515,26 @@ func (parser *Parser) parseStructs() *errors.StackError {
         }
 
         parser.myLexer.StepCursorForward(2)
+        base := ""
+        parameters := parser.genericStructs[name.Value]
+
+        if tok := parser.myLexer.GetAtCursor(); tok.Value == "<" {
+            for tok.Value != ">" {
+                tok = parser.myLexer.Next()
+            }
+
+            parser.myLexer.StepCursorForward(1)
+        }
+
+        if tok := parser.myLexer.GetAtCursor(); tok.Value == "extends" {
+            baseToken := parser.myLexer.Next()
+            if baseToken == nil || baseToken.Is != types.TypeToken {
+                return errors.New(errors.ExpectedBaseForStruct, name.Value, token.RealPosition)
+            }
+
+            base = baseToken.Value
+            parser.myLexer.StepCursorForward(1)
+        }
 
         if tok := parser.myLexer.GetAtCursor(); tok.Value != "{" {
             return errors.New(errors.StructShouldStartWithCurlyBrace, token.RealPosition, tok.Value)
@@ -353,11 +1545,24 @@ func (parser *Parser) parseStructs() *errors.StackError {
             Name:                  name.Value,
             Fields:                []*types.Field{},
             OtherStructReferences: make(map[string][]int),
+            EnumReferences:        make(map[string][]int),
+            UnionReferences:       make(map[string][]int),
             EverReferenced:        false,
             ReferencedBy:          make(map[string]int),
+            Packed:                tokenIndex > 0 && parser.myLexer.TokenList[tokenIndex-1].Value == "packed",
+            ReservedIds:           make(map[int]bool),
+            ReservedNames:         make(map[string]bool),
+            Base:                  base,
+            TypeParameters:        parameters,
+        }
+
+        if len(parameters) > 0 {
+            parser.currentGeneric = _struct.Name
         }
 
-        err := parser.parseFields(&_struct, tokenIndex)
+        err := parser.parseFields(&_struct)
+        parser.currentGeneric = ""
+
         if err != nil {
             return err
         }
@@ -368,7 +1573,11 @@ func (parser *Parser) parseStructs() *errors.StackError {
             return errors.New(errors.AnotherStructWithSameNameExists, _struct.Name, val.Reference, _struct.Reference)
         }
 
-        if len(_struct.Fields) == 0 {
+        if reference, found := parser.findDeclaration(_struct.Name); found {
+            return errors.New(errors.AnotherDeclarationWithSameNameExists, _struct.Name, reference, _struct.Reference)
+        }
+
+        if len(_struct.Fields) == 0 && _struct.Base == "" {
             return errors.New(errors.AStructMustHaveAtleast1Field, _struct.Name)
         }
 
@@ -377,10 +1586,7 @@ func (parser *Parser) parseStructs() *errors.StackError {
         parser.myLexer.StepCursorForward(1)
         nextToken := parser.myLexer.LookAtFront()
 
-        switch nextToken.Value {
-        case "struct":
-        case "exports":
-        default:
+        if nextToken != nil && !language.DeclarationKeywords[nextToken.Value] {
             return errors.New(errors.UnexpectedTokenAfterStruct, nextToken.Value, nextToken.RealPosition)
         }
     }
@@ -388,25 +1594,145 @@ func (parser *Parser) parseStructs() *errors.StackError {
     return nil
 }
 
-func (parser *Parser) parseExports() *errors.StackError {
-    exportToken := parser.myLexer.TokenList[parser.myLexer.ExportReferences[0]]
-    parser.myLexer.JumpCursorAhead(parser.myLexer.ExportReferences[0] + 1)
-    exportNameToken := parser.myLexer.GetAtCursor()
+func (parser *Parser) parseUnionArms(_union *types.Union) *errors.StackError {
+    armNames := map[string]bool{}
 
-    if _, err := util.IsAValidName(exportNameToken.Value); err != nil {
-        return err
+    for token := parser.myLexer.Next(); token.Value != "}"; token = parser.myLexer.Next() {
+        if token.Is == types.InvalidToken {
+            return errors.New(errors.CurlyBraceNotClosed, _union.Reference)
+        }
+        if token.Is != types.TypeToken {
+            return errors.New(errors.UnexpectedTokenInUnion, token.Value, token.RealPosition, _union.Name)
+        }
+        if _, err := util.IsAValidName(token.Value); err != nil {
+            return err
+        }
+
+        if armNames[token.Value] {
+            return errors.New(errors.AnotherUnionArmWithSameNameExists, _union.Name, token.Value)
+        }
+
+        parser.myLexer.Next()
+        _type, err := parser.parseType()
+        if err != nil {
+            return err
+        }
+
+        if _type.IsOptional {
+            return errors.New(errors.UnionArmCantBeOptional, token.Value, _union.Name)
+        }
+
+        arm := types.Field{
+            Name:      token.Value,
+            Type:      &_type,
+            Reference: token.RealPosition,
+        }
+
+        armNames[arm.Name] = true
+        noteReference(&arm, len(_union.Arms), _union.OtherStructReferences, _union.EnumReferences, _union.UnionReferences)
+
+        _union.Arms = append(_union.Arms, &arm)
     }
-    if exportNameToken.Is != types.ExportNameToken {
-        return errors.New(errors.ExpectedNameForExport, exportToken.RealPosition)
+
+    return nil
+}
+
+func (parser *Parser) parseUnions() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.UnionReferences {
+        parser.myLexer.JumpCursorAhead(tokenIndex)
+        token := parser.myLexer.GetAtCursor()
+
+        name := parser.myLexer.LookAtFront()
+        if name == nil {
+            return errors.New(errors.ExpectedNameForUnion, token.RealPosition)
+        }
+        if _, err := util.IsAValidName(name.Value); err != nil {
+            return err
+        }
+        if name.Is != types.UnionNameToken {
+            return errors.New(errors.ExpectedNameForUnion, token.RealPosition)
+        }
+
+        if language.DefaultTypes[name.Value] == true {
+            return errors.New(errors.InvalidUnionNaming, token.RealPosition, name.Value)
+        }
+
+        parser.myLexer.StepCursorForward(2)
+
+        if tok := parser.myLexer.GetAtCursor(); tok.Value != "{" {
+            return errors.New(errors.UnionShouldStartWithCurlyBrace, token.RealPosition, tok.Value)
+        }
+
+        _union := types.Union{
+            Reference:             token.RealPosition,
+            Name:                  name.Value,
+            Arms:                  []*types.Field{},
+            OtherStructReferences: make(map[string][]int),
+            EnumReferences:        make(map[string][]int),
+            UnionReferences:       make(map[string][]int),
+            EverReferenced:        false,
+            ReferencedBy:          make(map[string]int),
+        }
+
+        if err := parser.parseUnionArms(&_union); err != nil {
+            return err
+        }
+
+        if reference, found := parser.findDeclaration(_union.Name); found {
+            return errors.New(errors.AnotherDeclarationWithSameNameExists, _union.Name, reference, _union.Reference)
+        }
+
+        if len(_union.Arms) == 0 {
+            return errors.New(errors.AUnionMustHaveAtleast1Arm, _union.Name)
+        }
+
+        if len(_union.Arms) > language.MaxUnionArms {
+            return errors.New(errors.TooManyUnionArms, _union.Name, len(_union.Arms), language.MaxUnionArms)
+        }
+
+        parser.Result.Unions[_union.Name] = &_union
+
+        nextToken := parser.myLexer.LookAtFront()
+        if nextToken != nil && !language.DeclarationKeywords[nextToken.Value] {
+            return errors.New(errors.UnexpectedTokenAfterUnion, nextToken.Value, nextToken.RealPosition)
+        }
     }
 
-    _, found := parser.Result.Structs[exportNameToken.Value]
+    return nil
+}
+
+func (parser *Parser) parseExports() *errors.StackError {
+    for _, tokenIndex := range parser.myLexer.ExportReferences {
+        exportToken := parser.myLexer.TokenList[tokenIndex]
+        parser.myLexer.JumpCursorAhead(tokenIndex + 1)
+        exportNameToken := parser.myLexer.GetAtCursor()
+
+        if exportNameToken.Is != types.ExportNameToken {
+            return errors.New(errors.ExpectedNameForExport, exportToken.RealPosition)
+        }
+        if _, err := util.IsAValidName(exportNameToken.Value); err != nil {
+            return err
+        }
+
+        _, isStruct := parser.Result.Structs[exportNameToken.Value]
+        _, isUnion := parser.Result.Unions[exportNameToken.Value]
+
+        if !isStruct && !isUnion {
+            return errors.New(errors.DidntFoundAStructToExport, exportNameToken.Value)
+        }
+
+        if _, isGeneric := parser.getTypeParameters(exportNameToken.Value); isGeneric {
+            return errors.New(errors.GenericStructCantBeExported, exportNameToken.Value)
+        }
 
-    if !found {
-        return errors.New(errors.DidntFoundAStructToExport, exportNameToken.Value)
+        if slices.Contains(parser.Result.Exports, exportNameToken.Value) {
+            return errors.New(errors.ExportedMoreThanOnce, exportNameToken.Value, exportToken.RealPosition)
+        }
+
+        parser.Result.Exports = append(parser.Result.Exports, exportNameToken.Value)
     }
 
-    parser.Result.Exports = exportNameToken.Value
+    parser.Result.Name = parser.Result.Exports[0]
 
     return nil
 }
@@ -416,9 +1742,8 @@ func (parser *Parser) checkPath(currentName string, path []string, visited map[s
         path = append(path, currentName)
 
         if len(visited) == 1 {
-            return errors.New(errors.AStructCantReferenceItself, currentName, getConcatenatedNames(
-                parser.Result.Structs[currentName].Fields, parser.Result.Structs[currentName].OtherStructReferences[currentName],
-            ))
+            fields, nodeReferences, _ := parser.getReferenceNode(currentName)
+            return errors.New(errors.AStructCantReferenceItself, currentName, getConcatenatedNames(fields, references.GetRequiredReferences(fields, nodeReferences[currentName], currentName)))
         }
 
         pathStr := strings.Join(path, " -> ")
@@ -426,7 +1751,7 @@ func (parser *Parser) checkPath(currentName string, path []string, visited map[s
         return errors.New(errors.CyclicReference, pathStr)
     }
 
-    currentStruct, exists := parser.Result.Structs[currentName]
+    fields, nodeReferences, exists := parser.getReferenceNode(currentName)
     if !exists {
         return errors.New(errors.UnknownType, currentName, path[0])
     }
@@ -434,17 +1759,140 @@ func (parser *Parser) checkPath(currentName string, path []string, visited map[s
     visited[currentName] = true
     path = append(path, currentName)
 
-    for neighborName := range currentStruct.OtherStructReferences {
+    for neighborName, indexes := range nodeReferences {
+        if len(references.GetRequiredReferences(fields, indexes, neighborName)) == 0 {
+            if _, _, neighborExists := parser.getReferenceNode(neighborName); !neighborExists {
+                return errors.New(errors.UnknownType, neighborName, path[0])
+            }
+
+            continue
+        }
+
         if err := parser.checkPath(neighborName, path, visited); err != nil {
             return err
         }
     }
 
+    // Base is sent inside struct, so it is always a required reference.
+    if base := parser.getBase(currentName); base != "" {
+        if err := parser.checkPath(base, path, visited); err != nil {
+            return err
+        }
+    }
+
     delete(visited, currentName)
 
     return nil
 }
 
+func (parser *Parser) getNeighborNames(name string) []string {
+    _, nodeReferences, _ := parser.getReferenceNode(name)
+
+    neighborNames := slices.Collect(maps.Keys(nodeReferences))
+    if base := parser.getBase(name); base != "" {
+        neighborNames = append(neighborNames, base)
+    }
+
+    return neighborNames
+}
+
+// Structs and unions reaching themselves get a depth limit on read so payloads can't exhaust stack.
+func (parser *Parser) markRecursions() {
+    for name, _struct := range parser.Result.Structs {
+        _struct.Recursive = references.Reaches(name, name, parser.getNeighborNames, map[string]bool{})
+    }
+
+    for name, _union := range parser.Result.Unions {
+        _union.Recursive = references.Reaches(name, name, parser.getNeighborNames, map[string]bool{})
+    }
+}
+
+// Collects fields of base chain root first, erroring on chains that loop or names that collide.
+func (parser *Parser) resolveBase(_struct *types.Struct) *errors.StackError {
+    chain := []*types.Struct{}
+    path := []string{_struct.Name}
+
+    for current := _struct; current.Base != ""; {
+        base, found := parser.Result.Structs[current.Base]
+        if !found || len(base.TypeParameters) > 0 {
+            return errors.New(errors.BaseMustBeAStruct, current.Name, current.Base)
+        }
+
+        path = append(path, base.Name)
+
+        if base == _struct || slices.Contains(chain, base) {
+            return errors.New(errors.ExtensionCycle, _struct.Name, strings.Join(path, " -> "))
+        }
+
+        chain = append(chain, base)
+        current = base
+    }
+
+    inherited := []*types.Field{}
+    owners := map[string]string{}
+
+    for i := len(chain) - 1; i >= 0; i-- {
+        for _, field := range chain[i].Fields {
+            if owner, exists := owners[field.Name]; exists {
+                return errors.New(errors.InheritedFieldCollision, field.Name, chain[i].Name, owner)
+            }
+
+            owners[field.Name] = chain[i].Name
+            inherited = append(inherited, field)
+        }
+    }
+
+    for _, field := range _struct.Fields {
+        if owner, exists := owners[field.Name]; exists {
+            return errors.New(errors.InheritedFieldCollision, field.Name, _struct.Name, owner)
+        }
+    }
+
+    _struct.Inherited = inherited
+
+    return nil
+}
+
+func (parser *Parser) getBase(name string) string {
+    if _struct, found := parser.Result.Structs[name]; found {
+        return _struct.Base
+    }
+
+    return ""
+}
+
+// Deprecated fields are only warned about when an export still sends them, with the path it reaches them through.
+func (parser *Parser) warnDeprecatedFields() {
+    for _, exportName := range parser.Result.Exports {
+        paths := map[string][]string{exportName: {exportName}}
+        queue := []string{exportName}
+
+        for len(queue) > 0 {
+            name := queue[0]
+            queue = queue[1:]
+
+            fields, _, _ := parser.getReferenceNode(name)
+
+            for _, field := range fields {
+                if field.Deprecated {
+                    ui.Log(config.GRANDMASTER, "warning", fmt.Sprintf("Field '%s' of struct '%s' at '%s' is deprecated but export '%s' still sends it through '%s'.",
+                        field.Name, name, field.Reference, exportName, strings.Join(paths[name], " -> ")))
+                }
+            }
+
+            neighborNames := parser.getNeighborNames(name)
+            slices.Sort(neighborNames)
+
+            for _, neighborName := range neighborNames {
+                if _, seen := paths[neighborName]; !seen {
+                    paths[neighborName] = append(slices.Clone(paths[name]), neighborName)
+                    queue = append(queue, neighborName)
+                }
+            }
+        }
+    }
+}
+
 func (parser *Parser) detectCycles() *errors.StackError {
     for name := range parser.Result.Structs {
         path := []string{}
@@ -455,72 +1903,194 @@ func (parser *Parser) detectCycles() *errors.StackError {
         }
     }
 
+    for name := range parser.Result.Unions {
+        path := []string{}
+        visited := make(map[string]bool)
+
+        if err := parser.checkPath(name, path, visited); err != nil {
+            return err
+        }
+    }
+
     return nil
 }
 
 func (parser *Parser) semanticAnalyze() *errors.StackError {
-    for currentName, currentStruct := range parser.Result.Structs { // 3?
-        for referencedName := range currentStruct.OtherStructReferences {
-            targetStruct, found := parser.Result.Structs[referencedName]
+    for _, name := range slices.Sorted(maps.Keys(parser.Result.Structs)) {
+        if err := parser.resolveBase(parser.Result.Structs[name]); err != nil {
+            return err
+        }
+    }
 
-            if !found {
-                continue
+    for currentName, currentStruct := range parser.Result.Structs {
+        parser.markReferences(currentName, currentStruct.OtherStructReferences, currentStruct.EnumReferences, currentStruct.UnionReferences)
+    }
+
+    for currentName, currentUnion := range parser.Result.Unions {
+        parser.markReferences(currentName, currentUnion.OtherStructReferences, currentUnion.EnumReferences, currentUnion.UnionReferences)
+    }
+
+    parser.markExtensions()
+    parser.markRecursions()
+
+    // With multiple exports every export gets its own helpers, so exports can embed each other.
+    // Recursive exports get them too since they reference themselves.
+    if len(parser.Result.Exports) == 1 {
+        exportName := parser.Result.Exports[0]
+
+        everReferenced, recursive, referencedBy := false, false, map[string]int{}
+        if exportStruct, isStruct := parser.Result.Structs[exportName]; isStruct {
+            everReferenced, recursive, referencedBy = exportStruct.EverReferenced, exportStruct.Recursive, exportStruct.ReferencedBy
+        } else if exportUnion, isUnion := parser.Result.Unions[exportName]; isUnion {
+            everReferenced, recursive, referencedBy = exportUnion.EverReferenced, exportUnion.Recursive, exportUnion.ReferencedBy
+        }
+
+        if everReferenced && !recursive {
+            keys := make([]string, 0, len(referencedBy))
+            for k := range referencedBy {
+                keys = append(keys, k)
             }
 
-            targetStruct.EverReferenced = true
-            targetStruct.ReferencedBy[currentName]++
+            return errors.New(errors.ExportStructCantBeReferenced, exportName, exportName, strings.Join(keys, ", "))
         }
     }
 
-    exportStruct := parser.Result.Structs[parser.Result.Exports]
-    if exportStruct.EverReferenced {
-        keys := make([]string, 0, len(exportStruct.ReferencedBy))
-        for k := range exportStruct.ReferencedBy {
-            keys = append(keys, k)
-        }
+    for _, name := range slices.Sorted(maps.Keys(parser.Result.Structs)) {
+        _struct := parser.Result.Structs[name]
 
-        return errors.New(errors.ExportStructCantBeReferenced, parser.Result.Exports, parser.Result.Exports, strings.Join(keys, ", "))
+        if err := parser.validateFieldIds(_struct); err != nil {
+            return err
+        }
     }
 
     if err := parser.detectCycles(); err != nil {
         return err
     }
 
+    parser.warnDeprecatedFields()
+
     return nil
 }
 
 // Constructor
 func New(myLexer *lexer.Lexer) *Parser {
+    return NewWithScheme(myLexer, &types.Scheme{
+        Exports:   []string{},
+        Name:      "",
+        Structs:   make(map[string]*types.Struct),
+        Enums:     make(map[string]*types.Enum),
+        Unions:    make(map[string]*types.Union),
+        Constants: make(map[string]*types.Constant),
+        Aliases:   make(map[string]*types.TypeAlias),
+    })
+}
+
+// Declarations are added to given scheme's maps, so multiple files can be parsed into one scheme.
+func NewWithScheme(myLexer *lexer.Lexer, scheme *types.Scheme) *Parser {
     return &Parser{
-        myLexer: myLexer,
+        myLexer:        myLexer,
+        genericStructs: make(map[string][]string),
+        currentGeneric: "",
         Result: types.Scheme{
-            Exports: "",
-            Structs: make(map[string]*types.Struct),
+            Exports:   []string{},
+            Name:      "",
+            Structs:   scheme.Structs,
+            Enums:     scheme.Enums,
+            Unions:    scheme.Unions,
+            Constants: scheme.Constants,
+            Aliases:   scheme.Aliases,
         },
     }
 }
 
 // Public Methods
+func (parser *Parser) ParseImports() ([]string, *errors.StackError) {
+    imports := []string{}
+
+    for _, tokenIndex := range parser.myLexer.ImportReferences {
+        importToken := parser.myLexer.TokenList[tokenIndex]
+        parser.myLexer.JumpCursorAhead(tokenIndex + 1)
+        pathToken := parser.myLexer.GetAtCursor()
+
+        if pathToken.Is != types.StringToken {
+            return nil, errors.New(errors.ExpectedPathForImport, importToken.RealPosition, pathToken.Value)
+        }
+
+        path, err := strconv.Unquote(pathToken.Value)
+        if err != nil || path == "" {
+            return nil, errors.New(errors.ExpectedPathForImport, importToken.RealPosition, pathToken.Value)
+        }
+
+        imports = append(imports, path)
+    }
+
+    parser.myLexer.ResetCursor()
+
+    return imports, nil
+}
+
+func (parser *Parser) ParseDeclarations() *errors.StackError {
+    // Constants go first so array sizes can use them.
+    if err := parser.parseConstants(); err != nil {
+        return err
+    }
+
+    parser.myLexer.ResetCursor()
+
+    if err0 := parser.parseEnums(); err0 != nil {
+        return err0
+    }
+
+    parser.myLexer.ResetCursor()
+
+    // Generic structs are known before any type is parsed so their instances can be checked where they are written.
+    if err := parser.collectGenericStructs(); err != nil {
+        return err
+    }
+
+    // Aliases go before structs and unions so their fields and arms can use them.
+    if err := parser.parseTypeAliases(); err != nil {
+        return err
+    }
+
+    parser.myLexer.ResetCursor()
+
+    if err1 := parser.parseStructs(); err1 != nil {
+        return err1
+    }
+
+    parser.myLexer.ResetCursor()
+
+    if err2 := parser.parseUnions(); err2 != nil {
+        return err2
+    }
+
+    parser.myLexer.ResetCursor()
+
+    return nil
+}
+
 func (parser *Parser) Parse() *errors.StackError {
     if parser.myLexer.Length() == 0 {
         return errors.New(errors.NotTokenized)
     }
 
-    if len(parser.myLexer.ExportReferences) > 1 ||
-        len(parser.myLexer.ExportReferences) == 0 ||
-        parser.myLexer.TokenList[parser.myLexer.ExportReferences[0]+1].Is != types.ExportNameToken {
+    if len(parser.myLexer.ExportReferences) == 0 {
         return errors.New(errors.Expected1Export, len(parser.myLexer.ExportReferences))
     }
 
-    if len(parser.myLexer.StructReferences) == 0 {
+    // Imported structs and unions count as well.
+    hasImportedDeclarations := len(parser.Result.Structs) > 0 || len(parser.Result.Unions) > 0
+
+    if !hasImportedDeclarations && len(parser.myLexer.StructReferences) == 0 && len(parser.myLexer.UnionReferences) == 0 {
         return errors.New(errors.ExpectedStructs)
     }
 
-    if len(parser.myLexer.FieldReferences) == 0 {
+    if !hasImportedDeclarations && len(parser.myLexer.FieldReferences) == 0 && len(parser.myLexer.UnionReferences) == 0 {
         return errors.New(errors.Expected1Field)
     }
 
-    if err1 := parser.parseStructs(); err1 != nil {
+    if err1 := parser.ParseDeclarations(); err1 != nil {
         return err1
     }
 
@@ -537,7 +2107,7 @@ func (parser *Parser) Parse() *errors.StackError {
 
 func (parser *Parser) Print() {
     ui.Log(config.FELLOWCRAFT, "info", "Printing parser results.")
-    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Exports: %s", parser.Result.Exports))
+    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Exports: %s", strings.Join(parser.Result.Exports, ", ")))
     ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Struct Count: %d", len(parser.Result.Structs)))
     ui.Log(config.APPRENTICE, "info", "STRUCTS")
 
@@ -545,5 +2115,31 @@ func (parser *Parser) Print() {
         parser.printSingleStruct(_struct)
     }
 
+    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Enum Count: %d", len(parser.Result.Enums)))
+    ui.Log(config.APPRENTICE, "info", "ENUMS")
+
+    for _, _enum := range parser.Result.Enums {
+        parser.printSingleEnum(_enum)
+    }
+
+    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Union Count: %d", len(parser.Result.Unions)))
+    ui.Log(config.APPRENTICE, "info", "UNIONS")
+
+    for _, _union := range parser.Result.Unions {
+        parser.printSingleUnion(_union)
+    }
+
+    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Type Alias Count: %d", len(parser.Result.Aliases)))
+
+    for _, alias := range parser.Result.Aliases {
+        ui.Log(config.MIDCLASS, "info", fmt.Sprintf("Type Alias '%s' At '%s', %s", alias.Name, alias.Reference, parser.getFieldTypeDescription(alias.Type)))
+    }
+
+    ui.Log(config.APPRENTICE, "info", fmt.Sprintf("Constant Count: %d", len(parser.Result.Constants)))
+
+    for _, constant := range parser.Result.Constants {
+        ui.Log(config.MIDCLASS, "info", fmt.Sprintf("Constant '%s' = %d, at '%s'", constant.Name, constant.Value, constant.Reference))
+    }
+
     ui.Log(config.FELLOWCRAFT, "info", "Finished printing parsing results.")
 }
@@ -5,20 +5,119 @@ var Keywords = map[string]bool{
     "struct":  true,
     "field":   true,
     "exports": true,
+    "enum":    true,
+    "union":   true,
+    "import":  true,
+    "const":   true,
+    "packed":  true,
+    "reserved": true,
+    "extends": true,
+    "type":    true,
 }
 
-var Operators = map[string]bool{"{": true, "}": true, "[": true, "]": true}
+// Keywords that can start a top level declaration.
+var DeclarationKeywords = map[string]bool{
+    "struct":  true,
+    "exports": true,
+    "enum":    true,
+    "union":   true,
+    "import":  true,
+    "const":   true,
+    "packed":  true,
+    "type":    true,
+}
+
+var MaxEnumMembers = 65536 // u16
+var MaxUnionArms = 256     // u8 discriminator
+
+var Operators = map[string]bool{"{": true, "}": true, "[": true, "]": true, "?": true, ":": true, "=": true, "..": true, "-": true, "(": true, ")": true, ",": true, "<": true, ">": true}
+
+// Bounds of integer types that can be constrained with a range.
+var IntegerBounds = map[string][2]int{
+    "u8":  {0, 255},            "i8":  {-128, 127},
+    "u16": {0, 65535},          "i16": {-32768, 32767},
+    "u32": {0, 4294967295},     "i32": {-2147483648, 2147483647},
+    "int": {-2147483648, 4294967295},
+}
+
+// Types that only hold whole numbers.
+var IntegerTypes = map[string]bool{
+    "u8":   true,   "i8":   true,
+    "u16":  true,   "i16":  true,
+    "u32":  true,   "i32":  true,
+    "vu32": true,   "vi32": true,
+    "u64":  true,   "i64":  true,
+    "u53":  true,   "int":  true,
+}
+
+// Luau representation notes emitted next to fields of these types.
+var TypeDocumentation = map[string]string{
+    "u64": "u64, values above 2^53 are rounded to the nearest representable number",
+    "i64": "i64, values beyond +-2^53 are rounded to the nearest representable number",
+    "u53": "u53, integers from 0 to 2^53 - 1, other values error on write",
+    "colorsequence": "colorsequence, at most 255 keypoints, times are f16",
+    "numbersequence": "numbersequence, at most 255 keypoints, times, values and envelopes are f16",
+    "datetime": "datetime, millisecond precision",
+}
+
+// Values given to fields missing from evolvable payloads, by Luau type.
+var RobloxTypeZeroValues = map[string]string{
+    "number":       "0",
+    "boolean":      "false",
+    "string":       "\"\"",
+    "Vector2":      "Vector2.zero",
+    "Vector3":      "Vector3.zero",
+    "Vector2int16": "Vector2int16.new()",
+    "Vector3int16": "Vector3int16.new()",
+    "CFrame":       "CFrame.identity",
+    "Color3":       "Color3.new()",
+    "UDim":           "UDim.new()",
+    "UDim2":          "UDim2.new()",
+    "Rect":           "Rect.new()",
+    "NumberRange":    "NumberRange.new(0)",
+    "ColorSequence":  "ColorSequence.new(Color3.new())",
+    "NumberSequence": "NumberSequence.new(0)",
+    "BrickColor":     "BrickColor.new(1)",
+    "DateTime":       "DateTime.fromUnixTimestampMillis(0)",
+    "Font":           "Font.fromEnum(Enum.Font.SourceSans)",
+}
+
+// Luau types values of 'any' can have, a value is sent as its index here followed by itself.
+// Tables send a vu32 entry count and then key and value of each entry as 'any'.
+var AnyTypeTags = []string{"nil", "boolean", "number", "string", "Vector3", "CFrame", "table"}
+
+// Types values of 'any' are sent as, by Luau type. Nil has no value and tables are handled by generated code.
+var AnyTypeWireTypes = map[string]string{
+    "boolean": "bool",
+    "number":  "f64",
+    "string":  "string_v",
+    "Vector3": "vector3",
+    "CFrame":  "cframe",
+}
+
+var QuantizedTypes = map[string]bool{"qfloat": true, "qvector3": true, "qcframe": true}
+
+var MaxRangeSpan = 4294967295 // Ranges are encoded as offsets from minimum in at most 32 bits.
 
 var DefaultTypes = map[string]bool{
     // Integers
     "u8":   true,   "i8":   true,
     "u16":  true,   "i16":  true,
     "u32":  true,   "i32":  true,
+    "vu32": true,   "vi32": true,   // LEB128, zigzag LEB128
+    "u64":  true,   "i64":  true,
+    "u53":  true,   // 7 bytes, exact in Luau numbers
+    "int":  true,   // Needs a range, encoded with the smallest width fitting it
 
     // Floats
     "f16":  true,   "f24":  true,
     "f32":  true,   "f64":  true,
 
+    // Quantized, need '(min, max, precision)' and are encoded with the smallest width fitting the steps
+    "qfloat":   true,
+    "qvector3": true,   // Each component quantized
+    "qcframe":  true,   // Position components quantized, rotation as in cframe_e
+
     // Vectors
     "vector2":      true,   "vector3":      true,   // f32 * 2, f32 * 3
     "vector2int16": true,   "vector3int16": true,   // i16 * 2, i16 * 3
@@ -33,9 +132,27 @@ var DefaultTypes = map[string]bool{
     "color3":       true,   // u8 * 3
     "color3_hdr":   true,   // f16 * 3
 
+    // UI
+    "udim":     true,   // f32 scale + i32 offset
+    "udim2":    true,   // udim * 2
+    "rect":     true,   // f32 * 4
+    "font":     true,   // string family + u16 weight + u8 style
+
+    // Ranges and sequences
+    "numberrange":      true,   // f32 * 2
+    "colorsequence":    true,   // u8 count + (f16 time + u8 * 3) per keypoint
+    "numbersequence":   true,   // u8 count + (f16 time + f16 value + f16 envelope) per keypoint
+
+    // Misc
+    "brickcolor":   true,   // u16 palette number
+    "datetime":     true,   // f64 unix milliseconds
+    "instance":     true,   // u16 index into instances sent next to buffer, 'instance<Part>' checks class on read
+    "any":          true,   // u8 tag + value, see AnyTypeTags
+
     // Other
     "string":   true, // u8 length + data
     "string_l": true, // u16 length + data
+    "string_v": true, // vu32 length + data
     "bool":     true, // Default -> 1 byte, In arrays -> 1 bit
 }
 
@@ -46,10 +163,17 @@ var DefaultTypesToRobloxTypes = map[string]string{
     "i16":          "number",
     "u32":          "number",
     "i32":          "number",
+    "vu32":         "number",
+    "vi32":         "number",
+    "u64":          "number",
+    "i64":          "number",
+    "u53":          "number",
+    "int":          "number",
     "f16":          "number",
     "f24":          "number",
     "f32":          "number",
     "f64":          "number",
+    "qfloat":       "number",
    
     "vector2":      "Vector2",
     "vector3":      "Vector3",
@@ -57,15 +181,30 @@ var DefaultTypesToRobloxTypes = map[string]string{
     "vector3int16": "Vector3int16",
     "vector2norm":  "Vector2",
     "vector3norm":  "Vector3",
+    "qvector3":     "Vector3",
 
     "cframe":       "CFrame",
     "cframe_e":     "CFrame",
     "cframe_q":     "CFrame",
+    "qcframe":      "CFrame",
 
     "color3":       "Color3",
     "color3_hdr":   "Color3",
 
+    "udim":             "UDim",
+    "udim2":            "UDim2",
+    "rect":             "Rect",
+    "font":             "Font",
+    "numberrange":      "NumberRange",
+    "colorsequence":    "ColorSequence",
+    "numbersequence":   "NumberSequence",
+    "brickcolor":       "BrickColor",
+    "datetime":         "DateTime",
+    "instance":         "Instance",
+    "any":              "any",
+
     "string":       "string",
     "string_l":     "string",
+    "string_v":     "string",
     "bool":         "boolean",
 }
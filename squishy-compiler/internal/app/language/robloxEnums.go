@@ -0,0 +1,239 @@
+package language
+
+// Public Variables
+
+// Roblox enum families usable as 'enum<Family>', their items are sent as u16 Value.
+var RobloxEnums = map[string]bool{
+    "AccessoryType": true,
+    "ActionType": true,
+    "ActuatorRelativeTo": true,
+    "ActuatorType": true,
+    "AdornCullingMode": true,
+    "AlignType": true,
+    "AlphaMode": true,
+    "AnimationPriority": true,
+    "AnimatorRetargetingMode": true,
+    "AppShellActionType": true,
+    "ApplyStrokeMode": true,
+    "AspectType": true,
+    "AssetType": true,
+    "AutomaticSize": true,
+    "AvatarItemType": true,
+    "Axis": true,
+    "BinType": true,
+    "BodyPart": true,
+    "BodyPartR15": true,
+    "BorderMode": true,
+    "BreakReason": true,
+    "BulkMoveMode": true,
+    "CameraMode": true,
+    "CameraType": true,
+    "CatalogCategoryFilter": true,
+    "CatalogSortType": true,
+    "CellBlock": true,
+    "CellMaterial": true,
+    "CellOrientation": true,
+    "CenterDialogType": true,
+    "ChatColor": true,
+    "ChatMode": true,
+    "ChatPrivacyMode": true,
+    "ChatStyle": true,
+    "CollisionFidelity": true,
+    "ComputerCameraMovementMode": true,
+    "ComputerMovementMode": true,
+    "ConnectionError": true,
+    "ConnectionState": true,
+    "ContextActionPriority": true,
+    "ContextActionResult": true,
+    "ControlMode": true,
+    "CoreGuiType": true,
+    "CreatorType": true,
+    "CurrencyType": true,
+    "CustomCameraMode": true,
+    "DevCameraOcclusionMode": true,
+    "DevComputerCameraMovementMode": true,
+    "DevComputerMovementMode": true,
+    "DevTouchCameraMovementMode": true,
+    "DevTouchMovementMode": true,
+    "DevelopmentLanguage": true,
+    "DeviceType": true,
+    "DialogBehaviorType": true,
+    "DialogPurpose": true,
+    "DialogTone": true,
+    "DominantAxis": true,
+    "DraggerCoordinateSpace": true,
+    "DraggerMovementMode": true,
+    "EasingDirection": true,
+    "EasingStyle": true,
+    "ElasticBehavior": true,
+    "EnviromentalPhysicsThrottle": true,
+    "ExplosionType": true,
+    "FillDirection": true,
+    "FilterResult": true,
+    "Font": true,
+    "FontSize": true,
+    "FontStyle": true,
+    "FontWeight": true,
+    "ForceLimitMode": true,
+    "FrameStyle": true,
+    "FramerateManagerMode": true,
+    "FriendRequestEvent": true,
+    "FriendStatus": true,
+    "FunctionalTestResult": true,
+    "GameAvatarType": true,
+    "GearGenreSetting": true,
+    "GearType": true,
+    "Genre": true,
+    "GraphicsMode": true,
+    "GuiState": true,
+    "HandlesStyle": true,
+    "HorizontalAlignment": true,
+    "HoverAnimateSpeed": true,
+    "HttpCachePolicy": true,
+    "HttpContentType": true,
+    "HttpError": true,
+    "HttpRequestType": true,
+    "HumanoidCollisionType": true,
+    "HumanoidDisplayDistanceType": true,
+    "HumanoidHealthDisplayType": true,
+    "HumanoidRigType": true,
+    "HumanoidStateType": true,
+    "IKCollisionsMode": true,
+    "InOut": true,
+    "InfoType": true,
+    "InputType": true,
+    "InterpolationThrottlingMode": true,
+    "JointCreationMode": true,
+    "KeyCode": true,
+    "KeyInterpolationMode": true,
+    "KeywordFilterType": true,
+    "Language": true,
+    "LeftRight": true,
+    "LevelOfDetailSetting": true,
+    "Limb": true,
+    "LineJoinMode": true,
+    "ListDisplayMode": true,
+    "ListenerType": true,
+    "Material": true,
+    "MaterialPattern": true,
+    "MembershipType": true,
+    "MeshPartDetailLevel": true,
+    "MeshPartHeadsAndAccessories": true,
+    "MeshType": true,
+    "MessageType": true,
+    "ModelLevelOfDetail": true,
+    "ModelStreamingMode": true,
+    "ModifierKey": true,
+    "MouseBehavior": true,
+    "MoveState": true,
+    "NameOcclusion": true,
+    "NetworkOwnership": true,
+    "NormalId": true,
+    "OutputLayoutMode": true,
+    "OverrideMouseIconBehavior": true,
+    "PackagePermission": true,
+    "PartType": true,
+    "ParticleEmitterShape": true,
+    "ParticleEmitterShapeInOut": true,
+    "ParticleEmitterShapeStyle": true,
+    "ParticleFlipbookLayout": true,
+    "ParticleFlipbookMode": true,
+    "ParticleOrientation": true,
+    "PathStatus": true,
+    "PathWaypointAction": true,
+    "PermissionLevelShown": true,
+    "PhysicsSimulationRate": true,
+    "Platform": true,
+    "PlaybackState": true,
+    "PlayerActions": true,
+    "PlayerChatType": true,
+    "PoseEasingDirection": true,
+    "PoseEasingStyle": true,
+    "PositionAlignmentMode": true,
+    "PrivilegeType": true,
+    "ProductPurchaseDecision": true,
+    "ProximityPromptExclusivity": true,
+    "ProximityPromptInputType": true,
+    "ProximityPromptStyle": true,
+    "QualityLevel": true,
+    "R15CollisionType": true,
+    "RaycastFilterType": true,
+    "RenderFidelity": true,
+    "RenderPriority": true,
+    "RenderingTestComparisonMethod": true,
+    "ReverbType": true,
+    "RigType": true,
+    "RollOffMode": true,
+    "RotationOrder": true,
+    "RotationType": true,
+    "RuntimeUndoBehavior": true,
+    "SaveFilter": true,
+    "SavedQualitySetting": true,
+    "ScaleType": true,
+    "ScreenInsets": true,
+    "ScreenOrientation": true,
+    "ScrollBarInset": true,
+    "ScrollingDirection": true,
+    "SelectionBehavior": true,
+    "SizeConstraint": true,
+    "SortDirection": true,
+    "SortOrder": true,
+    "SoundType": true,
+    "SpecialKey": true,
+    "StartCorner": true,
+    "Status": true,
+    "StreamOutBehavior": true,
+    "StreamingPauseMode": true,
+    "StudioStyleGuideColor": true,
+    "StudioStyleGuideModifier": true,
+    "Style": true,
+    "SurfaceConstraint": true,
+    "SurfaceGuiSizingMode": true,
+    "SurfaceType": true,
+    "SwipeDirection": true,
+    "TableMajorAxis": true,
+    "Technology": true,
+    "TeleportResult": true,
+    "TeleportState": true,
+    "TeleportType": true,
+    "TextChatMessageStatus": true,
+    "TextDirection": true,
+    "TextFilterContext": true,
+    "TextInputType": true,
+    "TextTruncate": true,
+    "TextXAlignment": true,
+    "TextYAlignment": true,
+    "TextureMode": true,
+    "TextureQueryType": true,
+    "ThreadPoolConfig": true,
+    "ThrottlingPriority": true,
+    "ThumbnailSize": true,
+    "ThumbnailType": true,
+    "TickCountSampleMethod": true,
+    "TopBottom": true,
+    "TouchCameraMovementMode": true,
+    "TouchMovementMode": true,
+    "TrackerMode": true,
+    "TriStateBoolean": true,
+    "TweenStatus": true,
+    "UITheme": true,
+    "UiMessageType": true,
+    "UserCFrame": true,
+    "UserInputState": true,
+    "UserInputType": true,
+    "VRTouchpad": true,
+    "VRTouchpadMode": true,
+    "VelocityConstraintMode": true,
+    "VerticalAlignment": true,
+    "VerticalScrollBarPosition": true,
+    "VibrationMotor": true,
+    "ViewMode": true,
+    "VirtualInputMode": true,
+    "VoiceChatState": true,
+    "WaterDirection": true,
+    "WaterForce": true,
+    "WeldConstraintPreserve": true,
+    "WrapLayerDebugMode": true,
+    "WrapTargetDebugMode": true,
+    "ZIndexBehavior": true,
+}
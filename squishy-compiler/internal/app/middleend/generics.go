@@ -0,0 +1,392 @@
+package middleend
+
+import (
+    "fmt"
+    "maps"
+    "slices"
+    "strings"
+
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/references"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/config"
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/errors"
+)
+
+// Functions
+func isGeneric(_struct *types.Struct) bool {
+    return len(_struct.TypeParameters) > 0
+}
+
+// Copies type replacing type parameters with arguments, containers take name and references of their new innermost element.
+func substituteType(_type *types.Type, parameters []string, arguments []*types.Type) *types.Type {
+    if _type.IsTypeParameter {
+        argument := substituteType(arguments[slices.Index(parameters, _type.Name)], nil, nil)
+        argument.IsOptional = argument.IsOptional || _type.IsOptional
+
+        return argument
+    }
+
+    copied := *_type
+
+    if _type.Key != nil {
+        copied.Key = substituteType(_type.Key, parameters, arguments)
+    }
+
+    if _type.Element != nil {
+        copied.Element = substituteType(_type.Element, parameters, arguments)
+        copied.Name = copied.Element.Name
+        copied.IsReferenceToAnotherStruct = copied.Element.IsReferenceToAnotherStruct
+        copied.IsReferenceToAnEnum = copied.Element.IsReferenceToAnEnum
+        copied.IsReferenceToAUnion = copied.Element.IsReferenceToAUnion
+    }
+
+    copied.TypeArguments = make([]*types.Type, 0, len(_type.TypeArguments))
+    for _, argument := range _type.TypeArguments {
+        copied.TypeArguments = append(copied.TypeArguments, substituteType(argument, parameters, arguments))
+    }
+
+    return &copied
+}
+
+func hasOptionalElement(_type *types.Type) bool {
+    if _type.Element == nil {
+        return false
+    }
+
+    return _type.Element.IsOptional || hasOptionalElement(_type.Element)
+}
+
+// Readable part of instance name, 'Page<[]item>' becomes 'Page_array_item'.
+func getMangledTypeName(_type *types.Type) string {
+    name := _type.Name
+
+    switch {
+    case _type.IsArray:
+        name = "array_" + getMangledTypeName(_type.Element)
+    case _type.IsSet:
+        name = "set_" + getMangledTypeName(_type.Element)
+    case _type.IsMap && _type.Key != nil:
+        name = "map_" + getMangledTypeName(_type.Key) + "_" + getMangledTypeName(_type.Element)
+    case _type.IsMap:
+        name = "map_" + getMangledTypeName(_type.Element)
+    case _type.RobloxEnum != "":
+        name = "Enum_" + _type.RobloxEnum
+    case _type.InstanceClass != "":
+        name = "instance_" + _type.InstanceClass
+    case len(_type.TypeArguments) > 0:
+        arguments := make([]string, 0, len(_type.TypeArguments))
+        for _, argument := range _type.TypeArguments {
+            arguments = append(arguments, getMangledTypeName(argument))
+        }
+
+        name += "_" + strings.Join(arguments, "_")
+    }
+
+    if _type.IsOptional {
+        return "optional_" + name
+    }
+
+    return name
+}
+
+// Everything that changes how a type is sent, two instances are the same struct only if their arguments have the same key.
+func getTypeKey(_type *types.Type) string {
+    key := fmt.Sprintf("%s,%t,%d,%t,%t,%t,%t,%t,%s,%s", _type.Name, _type.IsOptional, _type.ArraySize,
+        _type.IsArray, _type.IsMap, _type.IsShortMap, _type.IsSet, _type.IsVarintLength, _type.RobloxEnum, _type.InstanceClass)
+
+    if _type.Range != nil {
+        key += fmt.Sprintf(",(%d..%d)", _type.Range.Min, _type.Range.Max)
+    }
+
+    if _type.Quantization != nil {
+        key += fmt.Sprintf(",(%g,%g,%g)", _type.Quantization.Min, _type.Quantization.Max, _type.Quantization.Precision)
+    }
+
+    if _type.Key != nil {
+        key += ",key:" + getTypeKey(_type.Key)
+    }
+
+    if _type.Element != nil {
+        key += ",element:" + getTypeKey(_type.Element)
+    }
+
+    for _, argument := range _type.TypeArguments {
+        key += ",argument:" + getTypeKey(argument)
+    }
+
+    return "[" + key + "]"
+}
+
+func noteFieldReferences(fields []*types.Field,
+    structReferences map[string][]int, enumReferences map[string][]int, unionReferences map[string][]int,
+) {
+    clear(structReferences)
+    clear(enumReferences)
+    clear(unionReferences)
+
+    for index, field := range fields {
+        references.NoteTypeReference(field.Type, index, structReferences, enumReferences, unionReferences)
+    }
+}
+
+// Private Methods
+func (middleend *Middleend) hasGenerics() bool {
+    for _, _struct := range middleend.scheme.Structs {
+        if isGeneric(_struct) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func (middleend *Middleend) isInstantiated(name string) bool {
+    for _, _struct := range middleend.scheme.Structs {
+        if _struct.InstanceOf != nil && _struct.InstanceOf.Name == name {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Replaces instances like 'Page<item>' in type and its elements with the concrete struct created for them.
+func (middleend *Middleend) instantiateType(_type *types.Type, path []string) *errors.StackError {
+    if _type.Key != nil {
+        if err := middleend.instantiateType(_type.Key, path); err != nil {
+            return err
+        }
+    }
+
+    if _type.Element != nil {
+        if err := middleend.instantiateType(_type.Element, path); err != nil {
+            return err
+        }
+
+        _type.Name = _type.Element.Name
+        return nil
+    }
+
+    if len(_type.TypeArguments) == 0 {
+        return nil
+    }
+
+    for _, argument := range _type.TypeArguments {
+        if err := middleend.instantiateType(argument, path); err != nil {
+            return err
+        }
+    }
+
+    name, err := middleend.instantiate(_type, path)
+    if err != nil {
+        return err
+    }
+
+    _type.Name = name
+    _type.TypeArguments = nil
+
+    return nil
+}
+
+// Creates the concrete struct of a generic struct instance once, later instances with the same arguments reuse it.
+func (middleend *Middleend) instantiate(_type *types.Type, path []string) (string, *errors.StackError) {
+    // Optional instances are the same struct, only the field holding them is optional.
+    instanceOf := *_type
+    instanceOf.IsOptional = false
+
+    key := getTypeKey(&instanceOf)
+    if name, found := middleend.instances[key]; found {
+        return name, nil
+    }
+
+    generic := middleend.scheme.Structs[_type.Name]
+
+    if len(path) > config.MaxGenericInstanceDepth {
+        return "", errors.New(errors.GenericInstantiationTooDeep, generic.Name, config.MaxGenericInstanceDepth, strings.Join(path, " -> "))
+    }
+
+    baseName := getMangledTypeName(&instanceOf)
+    name := baseName
+
+    for suffix := 2; ; suffix++ {
+        existing, exists := middleend.scheme.Structs[name]
+        if !exists {
+            break
+        }
+        if existing.InstanceOf == nil {
+            return "", errors.New(errors.GenericInstanceNameCollision, name, generic.Name, existing.Reference)
+        }
+
+        name = fmt.Sprintf("%s_%d", baseName, suffix)
+    }
+
+    instance := &types.Struct{
+        Reference:             generic.Reference,
+        Name:                  name,
+        Fields:                make([]*types.Field, 0, len(generic.Fields)),
+        OtherStructReferences: make(map[string][]int),
+        EnumReferences:        make(map[string][]int),
+        UnionReferences:       make(map[string][]int),
+        EverReferenced:        true,
+        ReferencedBy:          make(map[string]int),
+        Packed:                generic.Packed,
+        ReservedIds:           generic.ReservedIds,
+        ReservedNames:         generic.ReservedNames,
+        Base:                  generic.Base,
+        Inherited:             generic.Inherited,
+        InstanceOf:            &instanceOf,
+    }
+
+    for _, field := range generic.Fields {
+        copied := *field
+        copied.Type = substituteType(field.Type, generic.TypeParameters, _type.TypeArguments)
+
+        if hasOptionalElement(copied.Type) {
+            return "", errors.New(errors.ElementCantBeOptional, generic.Reference)
+        }
+
+        instance.Fields = append(instance.Fields, &copied)
+    }
+
+    // Registered before its fields are instantiated so instances referencing themselves find it.
+    middleend.instances[key] = name
+    middleend.scheme.Structs[name] = instance
+
+    for _, field := range instance.Fields {
+        if err := middleend.instantiateType(field.Type, append(path, name)); err != nil {
+            return "", err
+        }
+    }
+
+    return name, nil
+}
+
+func (middleend *Middleend) getReferenceNode(name string) ([]*types.Field, string, map[string][]int, map[string][]int) {
+    if _struct, found := middleend.scheme.Structs[name]; found {
+        return _struct.Fields, _struct.Base, _struct.OtherStructReferences, _struct.UnionReferences
+    }
+
+    _union := middleend.scheme.Unions[name]
+
+    return _union.Arms, "", _union.OtherStructReferences, _union.UnionReferences
+}
+
+func (middleend *Middleend) getNeighborNames(name string) []string {
+    _, base, structReferences, unionReferences := middleend.getReferenceNode(name)
+
+    neighborNames := append(getSortedKeys(structReferences), getSortedKeys(unionReferences)...)
+    if base != "" {
+        neighborNames = append(neighborNames, base)
+    }
+
+    return neighborNames
+}
+
+// Base is sent inside struct, so it is always required like fields that are sent every time.
+func (middleend *Middleend) getRequiredNeighborNames(name string) []string {
+    fields, base, structReferences, unionReferences := middleend.getReferenceNode(name)
+    required := []string{}
+
+    for _, nodeReferences := range []map[string][]int{structReferences, unionReferences} {
+        for _, neighborName := range getSortedKeys(nodeReferences) {
+            if len(references.GetRequiredReferences(fields, nodeReferences[neighborName], neighborName)) > 0 {
+                required = append(required, neighborName)
+            }
+        }
+    }
+
+    if base != "" {
+        required = append(required, base)
+    }
+
+    return required
+}
+
+// Arguments can close a cycle generic struct alone didn't have, 'node' holding a 'Wrap<node>' that holds its 'T'.
+func (middleend *Middleend) checkRequiredPath(currentName string, path []string, checked map[string]bool) *errors.StackError {
+    if slices.Contains(path, currentName) {
+        return errors.New(errors.CyclicReference, strings.Join(append(path, currentName), " -> "))
+    }
+
+    if checked[currentName] {
+        return nil
+    }
+
+    for _, neighborName := range middleend.getRequiredNeighborNames(currentName) {
+        if err := middleend.checkRequiredPath(neighborName, append(path, currentName), checked); err != nil {
+            return err
+        }
+    }
+
+    checked[currentName] = true
+
+    return nil
+}
+
+// Generic structs only exist in Luau types, every instance of them becomes a concrete struct that gets functions.
+func (middleend *Middleend) monomorphize() *errors.StackError {
+    if !middleend.hasGenerics() {
+        return nil
+    }
+
+    for _, name := range slices.Sorted(maps.Keys(middleend.scheme.Aliases)) {
+        if err := middleend.instantiateType(middleend.scheme.Aliases[name].Type, []string{name}); err != nil {
+            return err
+        }
+    }
+
+    for _, name := range slices.Sorted(maps.Keys(middleend.scheme.Structs)) {
+        _struct := middleend.scheme.Structs[name]
+        if isGeneric(_struct) || _struct.InstanceOf != nil {
+            continue
+        }
+
+        for _, field := range _struct.Fields {
+            if err := middleend.instantiateType(field.Type, []string{name}); err != nil {
+                return err
+            }
+        }
+    }
+
+    for _, name := range slices.Sorted(maps.Keys(middleend.scheme.Unions)) {
+        for _, arm := range middleend.scheme.Unions[name].Arms {
+            if err := middleend.instantiateType(arm.Type, []string{name}); err != nil {
+                return err
+            }
+        }
+    }
+
+    // References and recursions are found again now that instances are structs of their own.
+    names := []string{}
+
+    for name, _struct := range middleend.scheme.Structs {
+        if !isGeneric(_struct) {
+            noteFieldReferences(_struct.Fields, _struct.OtherStructReferences, _struct.EnumReferences, _struct.UnionReferences)
+            names = append(names, name)
+        }
+    }
+
+    for name, _union := range middleend.scheme.Unions {
+        noteFieldReferences(_union.Arms, _union.OtherStructReferences, _union.EnumReferences, _union.UnionReferences)
+        names = append(names, name)
+    }
+
+    slices.Sort(names)
+    checked := make(map[string]bool)
+
+    for _, name := range names {
+        if err := middleend.checkRequiredPath(name, []string{}, checked); err != nil {
+            return err
+        }
+    }
+
+    for _, name := range names {
+        if _struct, isStruct := middleend.scheme.Structs[name]; isStruct {
+            _struct.Recursive = references.Reaches(name, name, middleend.getNeighborNames, map[string]bool{})
+        } else {
+            middleend.scheme.Unions[name].Recursive = references.Reaches(name, name, middleend.getNeighborNames, map[string]bool{})
+        }
+    }
+
+    return nil
+}
@@ -23,6 +23,9 @@ package middleend
 
 import (
     "fmt"
+    "maps"
+    "slices"
+    "sort"
     "strings"
 
     "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/language"
@@ -38,51 +41,143 @@ func getSizeForField(field *types.Field) int {
     size += len(field.Name)
     size += len(field.Type.Name)
 
-    if field.Type.IsArray || field.Type.IsMap { // { [number] : type } { [string] : type }
+    for _type := field.Type; _type.IsArray || _type.IsMap; _type = _type.Element { // { [number] : type } { [string] : type }
         size += 15
     }
 
+    if field.Type.IsOptional {
+        size += 1
+    }
+
     return size
 }
 
-func getFieldTypeString(field *types.Field) string {
-    out := "    " + field.Name + " : "
+func getTypeString(_type *types.Type) string {
+    if _type.AliasName != "" {
+        if _type.IsOptional {
+            return _type.AliasName + "?"
+        }
 
-    typeName := field.Type.Name
+        return _type.AliasName
+    }
+
+    out := ""
+    typeName := _type.Name
 
     if _, isDefault := language.DefaultTypes[typeName]; isDefault {
         typeName = language.DefaultTypesToRobloxTypes[typeName]
+    } else if _type.RobloxEnum != "" {
+        typeName = "Enum." + _type.RobloxEnum
     }
 
-    if field.Type.IsArray {
-        out += "{ [number] : " + typeName + " }"
-    } else if field.Type.IsMap {
-        out += "{ [string] : " + typeName + " }"
+    if _type.InstanceClass != "" {
+        typeName = _type.InstanceClass
+    }
+
+    if _type.IsArray {
+        out += "{ [number] : " + getTypeString(_type.Element) + " }"
+    } else if len(_type.TypeArguments) > 0 {
+        arguments := make([]string, 0, len(_type.TypeArguments))
+        for _, argument := range _type.TypeArguments {
+            arguments = append(arguments, getTypeString(argument))
+        }
+
+        out += typeName + "<" + strings.Join(arguments, ", ") + ">"
+    } else if _type.IsSet {
+        out += "{ [" + getTypeString(_type.Element) + "] : true }"
+    } else if _type.IsMap {
+        keyString := "string"
+
+        if _type.Key != nil {
+            keyString = getTypeString(_type.Key)
+        }
+
+        out += "{ [" + keyString + "] : " + getTypeString(_type.Element) + " }"
     } else {
         out += typeName
     }
 
-    out += ";\n"
+    if _type.IsOptional {
+        out += "?"
+    }
 
     return out
 }
 
+func getFieldTypeString(field *types.Field) string {
+    out := ""
+
+    if field.Deprecated {
+        out += "    --- @deprecated\n"
+    }
+
+    if documentation, found := language.TypeDocumentation[field.Type.Name]; found {
+        return out + "    " + field.Name + " : " + getTypeString(field.Type) + "; -- " + documentation + "\n"
+    }
+
+    return out + "    " + field.Name + " : " + getTypeString(field.Type) + ";\n"
+}
+
+func getUnionTypeString(_union *types.Union) string {
+    arms := make([]string, 0, len(_union.Arms))
+
+    for _, arm := range _union.Arms {
+        arms = append(arms, fmt.Sprintf("{ kind : \"%s\"; value : %s }", arm.Name, getTypeString(arm.Type)))
+    }
+
+    return strings.Join(arms, "\n    | ")
+}
+
+// Extending structs are an intersection of their base and own fields.
+func getStructTypeStart(_struct *types.Struct) string {
+    if _struct.Base != "" {
+        return _struct.Base + " & {\n"
+    }
+
+    return "{\n"
+}
+
+func getSortedKeys(m map[string][]int) []string {
+    keys := make([]string, 0, len(m))
+
+    for k := range m {
+        keys = append(keys, k)
+    }
+
+    sort.Strings(keys)
+
+    return keys
+}
+
 // Public Structs
 
 /*
     @object Middleend
 
     @privatevariables
-    *   @privatevariable sortedStructs : []string ;; List of structs sorted in dependency-reference connection.
+    *   @privatevariable sortedStructs : []string ;; List of structs and enums sorted in dependency-reference connection.
     *   @privatevariable scheme : *types.Scheme ;; Pointer to scheme created by frontend.
     *   @privatevariable exportBuilder : strings.Builder ;; String builder for lua export type.
     *   @privatevariable typeBuilder : strings.Builder ;; String builder for lua type.
+    *   @privatevariable instances : map[string]string ;; Names of concrete structs created for generic struct instances, by their type key.
     @privatemethods
+    *   @privatemethod hasGenerics
+    *   @privatemethod isInstantiated
+    *   @privatemethod instantiateType
+    *   @privatemethod instantiate
+    *   @privatemethod getReferenceNode
+    *   @privatemethod getNeighborNames
+    *   @privatemethod getRequiredNeighborNames
+    *   @privatemethod checkRequiredPath
+    *   @privatemethod monomorphize
+    *   @privatemethod noteEnumsToCareAbout
     *   @privatemethod noteStructsToCareAbout
     *   @privatemethod sortStructs
     *   @privatemethod writeType
+    *   @privatemethod writeEnumType
     *   @privatemethod writeTypes
     *   @privatemethod writeExport
+    *   @privatemethod writeExports
     @publicmethods
     *   @publicmethod Work
     *   @publicmethod GetResults
@@ -95,6 +190,7 @@ type Middleend struct {
     scheme        *types.Scheme
     exportBuilder strings.Builder
     typeBuilder   strings.Builder
+    instances     map[string]string
 }
 
 // Constructor
@@ -104,21 +200,49 @@ func New(scheme *types.Scheme) *Middleend {
         scheme:        scheme,
         exportBuilder: strings.Builder{},
         typeBuilder:   strings.Builder{},
+        instances:     make(map[string]string),
     }
 }
 
 // Private Methods
+func (middleend *Middleend) noteEnumsToCareAbout() []string {
+    notedEnums := []string{}
+
+    for name, _enum := range middleend.scheme.Enums {
+        if !_enum.EverReferenced {
+            continue
+        }
+
+        notedEnums = append(notedEnums, name)
+    }
+
+    sort.Strings(notedEnums)
+
+    return notedEnums
+}
+
+// Exports are noted too when another export references them, since they need helpers then.
 func (middleend *Middleend) noteStructsToCareAbout() []string {
     notedStructs := []string{}
 
     for name, _struct := range middleend.scheme.Structs {
-        if name == middleend.scheme.Exports || !_struct.EverReferenced {
+        if !_struct.EverReferenced || isGeneric(_struct) {
             continue
         }
 
         notedStructs = append(notedStructs, name)
     }
 
+    for name, _union := range middleend.scheme.Unions {
+        if !_union.EverReferenced {
+            continue
+        }
+
+        notedStructs = append(notedStructs, name)
+    }
+
+    sort.Strings(notedStructs)
+
     return notedStructs
 }
 
@@ -128,20 +252,42 @@ func (middleend *Middleend) sortStructs() { // Not likely
     // If a struct needs another struct, its below that another struct.
     // Topological Sorting??
     // This must run after semantic analysis (cyclic dependency check)
+    // Enums dont depend on anything so they always go first.
+    // Unions are sorted together with structs since their arms can reference structs and vice versa.
 
+    notedEnums := middleend.noteEnumsToCareAbout()
     notedStructs := middleend.noteStructsToCareAbout()
 
     visited := make(map[string]bool)
-    sortedStructs := make([]string, 0, len(notedStructs))
+    visiting := make(map[string]bool) // Recursive structs reach themselves, Luau types and functions don't need an order between them.
+    sortedStructs := make([]string, 0, len(notedEnums)+len(notedStructs))
+    sortedStructs = append(sortedStructs, notedEnums...)
 
     var visit func(name string)
     visit = func(name string) {
-        if visited[name] {
+        if visited[name] || visiting[name] {
             return
         }
 
+        visiting[name] = true
+
         if _struct, exists := middleend.scheme.Structs[name]; exists {
-            for dependencyName := range _struct.OtherStructReferences {
+            if _struct.Base != "" {
+                visit(_struct.Base)
+            }
+            for _, dependencyName := range getSortedKeys(_struct.OtherStructReferences) {
+                visit(dependencyName)
+            }
+            for _, dependencyName := range getSortedKeys(_struct.UnionReferences) {
+                visit(dependencyName)
+            }
+        }
+
+        if _union, exists := middleend.scheme.Unions[name]; exists {
+            for _, dependencyName := range getSortedKeys(_union.OtherStructReferences) {
+                visit(dependencyName)
+            }
+            for _, dependencyName := range getSortedKeys(_union.UnionReferences) {
                 visit(dependencyName)
             }
         }
@@ -157,9 +303,44 @@ func (middleend *Middleend) sortStructs() { // Not likely
     middleend.sortedStructs = sortedStructs
 }
 
+func (middleend *Middleend) writeEnumType(fetchedEnum *types.Enum) {
+    members := make([]string, 0, len(fetchedEnum.Members))
+
+    for _, member := range fetchedEnum.Members {
+        members = append(members, "\""+member+"\"")
+    }
+
+    middleend.typeBuilder.WriteString(fmt.Sprintf("type %s = %s\n", fetchedEnum.Name, strings.Join(members, " | ")))
+}
+
 func (middleend *Middleend) writeType(name string) {
+    if fetchedAlias, isAlias := middleend.scheme.Aliases[name]; isAlias {
+        middleend.typeBuilder.WriteString(fmt.Sprintf("type %s = %s\n", fetchedAlias.Name, getTypeString(fetchedAlias.Type)))
+        return
+    }
+
+    if fetchedEnum, isEnum := middleend.scheme.Enums[name]; isEnum {
+        middleend.writeEnumType(fetchedEnum)
+        return
+    }
+
+    if fetchedUnion, isUnion := middleend.scheme.Unions[name]; isUnion {
+        middleend.typeBuilder.WriteString(fmt.Sprintf("type %s =\n    %s\n", fetchedUnion.Name, getUnionTypeString(fetchedUnion)))
+        return
+    }
+
     fetchedStruct, _ := middleend.scheme.Structs[name]
 
+    if fetchedStruct.InstanceOf != nil {
+        middleend.typeBuilder.WriteString(fmt.Sprintf("type %s = %s\n", fetchedStruct.Name, getTypeString(fetchedStruct.InstanceOf)))
+        return
+    }
+
+    declaredName := fetchedStruct.Name
+    if isGeneric(fetchedStruct) {
+        declaredName += "<" + strings.Join(fetchedStruct.TypeParameters, ", ") + ">"
+    }
+
     expectedSize := 12 //type  = {}\n
     expectedSize += len(name)
     expectedSize += 2 // EOL
@@ -170,7 +351,7 @@ func (middleend *Middleend) writeType(name string) {
 
     middleend.typeBuilder.Grow(expectedSize)
 
-    middleend.typeBuilder.WriteString(fmt.Sprintf("type %s = {\n", fetchedStruct.Name))
+    middleend.typeBuilder.WriteString(fmt.Sprintf("type %s = %s", declaredName, getStructTypeStart(fetchedStruct)))
 
     for _, field := range fetchedStruct.Fields {
         middleend.typeBuilder.WriteString(getFieldTypeString(field))
@@ -180,10 +361,33 @@ func (middleend *Middleend) writeType(name string) {
 }
 
 func (middleend *Middleend) writeTypes() *errors.StackError {
-    for i, name := range middleend.sortedStructs {
+    names := []string{}
+
+    // Luau type aliases are hoisted, so aliases can go first even if they use structs below them.
+    for _, name := range slices.Sorted(maps.Keys(middleend.scheme.Aliases)) {
+        if middleend.scheme.Aliases[name].EverReferenced {
+            names = append(names, name)
+        }
+    }
+
+    // Generic types are hoisted too, their instances are aliases of them.
+    for _, name := range slices.Sorted(maps.Keys(middleend.scheme.Structs)) {
+        if isGeneric(middleend.scheme.Structs[name]) && middleend.isInstantiated(name) {
+            names = append(names, name)
+        }
+    }
+
+    for _, name := range middleend.sortedStructs {
+        // Exports already have their export type.
+        if !slices.Contains(middleend.scheme.Exports, name) {
+            names = append(names, name)
+        }
+    }
+
+    for i, name := range names {
         middleend.writeType(name);
 
-        if i != len(middleend.sortedStructs)-1 {
+        if i != len(names)-1 {
             middleend.typeBuilder.WriteString("\n")
         }
     }
@@ -191,8 +395,13 @@ func (middleend *Middleend) writeTypes() *errors.StackError {
     return nil
 }
 
-func (middleend *Middleend) writeExport()  {
-    exportStruct := middleend.scheme.Structs[middleend.scheme.Exports]
+func (middleend *Middleend) writeExport(name string)  {
+    if exportUnion, isUnion := middleend.scheme.Unions[name]; isUnion {
+        middleend.exportBuilder.WriteString(fmt.Sprintf("export type %s =\n    %s\n", exportUnion.Name, getUnionTypeString(exportUnion)))
+        return
+    }
+
+    exportStruct := middleend.scheme.Structs[name]
 
     expectedSize := 19 //export type  = {}\n
     expectedSize += len(exportStruct.Name)
@@ -204,7 +413,7 @@ func (middleend *Middleend) writeExport()  {
 
     middleend.exportBuilder.Grow(expectedSize)
 
-    middleend.exportBuilder.WriteString(fmt.Sprintf("export type %s = {\n", exportStruct.Name))
+    middleend.exportBuilder.WriteString(fmt.Sprintf("export type %s = %s", exportStruct.Name, getStructTypeStart(exportStruct)))
 
     for _, field := range exportStruct.Fields {
         middleend.exportBuilder.WriteString(getFieldTypeString(field))
@@ -213,11 +422,27 @@ func (middleend *Middleend) writeExport()  {
     middleend.exportBuilder.WriteString("}\n")
 }
 
+func (middleend *Middleend) writeExports() {
+    for i, name := range middleend.scheme.Exports {
+        middleend.writeExport(name)
+
+        if i != len(middleend.scheme.Exports)-1 {
+            middleend.exportBuilder.WriteString("\n")
+        }
+    }
+}
+
 // Public Methods
-func (middleend *Middleend) Work() {
+func (middleend *Middleend) Work() *errors.StackError {
+    if err := middleend.monomorphize(); err != nil {
+        return err
+    }
+
     middleend.sortStructs()
     middleend.writeTypes()
-    middleend.writeExport();
+    middleend.writeExports()
+
+    return nil
 }
 
 func (middleend *Middleend) GetResults() (string, string) {
@@ -0,0 +1,72 @@
+package references
+
+import (
+    "github.com/Cod2rDude/squishy/squishy-compiler/internal/app/types"
+)
+
+// Public Functions
+
+// Notes structs, enums and unions type uses under index of field holding it, elements, keys and type arguments included.
+func NoteTypeReference(_type *types.Type, index int,
+    structReferences map[string][]int, enumReferences map[string][]int, unionReferences map[string][]int,
+) {
+    if _type.Key != nil {
+        NoteTypeReference(_type.Key, index, structReferences, enumReferences, unionReferences)
+    }
+
+    if _type.Element != nil {
+        NoteTypeReference(_type.Element, index, structReferences, enumReferences, unionReferences)
+        return
+    }
+
+    for _, argument := range _type.TypeArguments {
+        NoteTypeReference(argument, index, structReferences, enumReferences, unionReferences)
+    }
+
+    name := _type.Name
+
+    switch {
+    case _type.IsReferenceToAnotherStruct:
+        structReferences[name] = append(structReferences[name], index)
+    case _type.IsReferenceToAnEnum:
+        enumReferences[name] = append(enumReferences[name], index)
+    case _type.IsReferenceToAUnion:
+        unionReferences[name] = append(unionReferences[name], index)
+    }
+}
+
+// Only fields referencing name directly can't end, optional fields, arrays and maps can be left empty.
+func GetRequiredReferences(fields []*types.Field, indexes []int, name string) []int {
+    required := []int{}
+
+    for _, index := range indexes {
+        _type := fields[index].Type
+
+        if !_type.IsOptional && !_type.IsArray && !_type.IsMap && _type.Name == name {
+            required = append(required, index)
+        }
+    }
+
+    return required
+}
+
+// Reports whether target can be reached from current through given neighbors, visited is shared between calls.
+func Reaches(currentName string, targetName string, getNeighborNames func(string) []string, visited map[string]bool) bool {
+    for _, neighborName := range getNeighborNames(currentName) {
+        if neighborName == targetName {
+            return true
+        }
+
+        if visited[neighborName] {
+            continue
+        }
+
+        visited[neighborName] = true
+
+        if Reaches(neighborName, targetName, getNeighborNames, visited) {
+            return true
+        }
+    }
+
+    return false
+}
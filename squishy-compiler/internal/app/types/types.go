@@ -12,6 +12,12 @@ const (
 	FieldNameToken
 	ExportNameToken
 	CommentToken
+	EnumNameToken
+	UnionNameToken
+	StringToken
+	ConstantNameToken
+	FloatToken
+	TypeAliasNameToken
 )
 
 // Public Structs
@@ -28,12 +34,43 @@ type Type struct {
 	ArraySize                   int
 	IsMap                       bool
 	IsShortMap                  bool
+	IsSet                       bool // Sets '{T}set' are maps from their elements to true, only elements are sent.
+	IsVarintLength              bool // Dynamic arrays '[vu32]T' and maps '{T}vmap' prefix their length with a vu32.
 	IsReferenceToAnotherStruct  bool
+	IsReferenceToAnEnum         bool
+	IsReferenceToAUnion         bool
+	IsOptional                  bool
+	Element                     *Type // Element type of arrays and maps, Name is the innermost element's name.
+	Key                         *Type // Key type of maps, nil means string keys.
+	Range                       *Range // Range of integer types, nil means unconstrained.
+	Quantization                *Quantization // Parameters of quantized types.
+	AliasName                   string // Name of type alias this type was resolved from, if any.
+	RobloxEnum                  string // Family of Roblox EnumItem, 'Material' for 'enum<Material>'.
+	InstanceClass               string // Class of instance, 'Part' for 'instance<Part>', empty means any Instance.
+	TypeArguments               []*Type // Arguments of a generic struct instance, 'item' for 'Page<item>'. Replaced with the concrete struct by middleend.
+	IsTypeParameter             bool // Type parameter of the generic struct it is used in, 'T' for 'struct Page<T>'.
+}
+
+type Range struct {
+	Min  int
+	Max  int
+	Bits int // Minimal bit width to encode an offset from Min.
+}
+
+type Quantization struct {
+	Min       float64
+	Max       float64
+	Precision float64
+	Bits      int // Minimal bit width to encode the steps between Min and Max.
 }
 
 type Field struct {
 	Name string
 	Type *Type
+	ID   int // Stable id of field, 0 means field has no id.
+	Deprecated bool
+	Default    string // Luau literal given to field when it is absent, empty means field has no default.
+	Reference  string // Position of field name in source.
 }
 
 type Struct struct {
@@ -41,11 +78,59 @@ type Struct struct {
 	Name                  string
 	Fields                []*Field
 	OtherStructReferences map[string][]int
+	EnumReferences        map[string][]int
+	UnionReferences       map[string][]int
 	EverReferenced        bool
 	ReferencedBy          map[string]int
+	Packed                bool // Bools, ranged ints, enums and presence bits share bytes through a bit writer.
+	ReservedIds           map[int]bool
+	ReservedNames         map[string]bool
+	Recursive             bool // Reaches itself through optional fields, arrays or maps, reads of it are depth limited.
+	Base                  string // Struct this one extends, empty means none.
+	Inherited             []*Field // Fields of base chain, root base's first. Sent before own fields.
+	TypeParameters        []string // Type parameters of generic struct, only its instances get functions.
+	InstanceOf            *Type // Generic struct and arguments this struct was created from, nil means it is not an instance.
+}
+
+type Union struct {
+	Reference             string
+	Name                  string
+	Arms                  []*Field
+	OtherStructReferences map[string][]int
+	EnumReferences        map[string][]int
+	UnionReferences       map[string][]int
+	EverReferenced        bool
+	ReferencedBy          map[string]int
+	Recursive             bool
+}
+
+type Enum struct {
+	Reference      string
+	Name           string
+	Members        []string
+	EverReferenced bool
+	ReferencedBy   map[string]int
+}
+
+type Constant struct {
+	Reference string
+	Name      string
+	Value     int
+}
+
+type TypeAlias struct {
+	Reference      string
+	Name           string
+	Type           *Type
+	EverReferenced bool
 }
 
 type Scheme struct {
-	Structs map[string]*Struct
-	Exports string
+	Structs   map[string]*Struct
+	Enums     map[string]*Enum
+	Unions    map[string]*Union
+	Constants map[string]*Constant
+	Aliases   map[string]*TypeAlias
+	Exports []string
+	Name    string // Name of the generated module, the export itself if there is only 1 export.
 }
@@ -5,6 +5,12 @@ const (
 	Version string = "1.0.0"
 )
 
+// Nesting limit of recursive structs and any tables on read, generated modules can change it with scheme.setMaxDepth.
+const DefaultMaxReadDepth int = 64
+
+// Nesting limit of generic struct instances, 'Box<T>' holding a '?Box<[]T>' would otherwise create instances forever.
+const MaxGenericInstanceDepth int = 16
+
 var DefaultExpectedFileExtensions = map[string]bool{
 	".squishy":  true,
 	".sqy": true,
@@ -21,31 +21,100 @@ var errorCodeToString = map[int]string{
     UnexpectedTokenAtStart: "First token at a file must be a keyword always but got '%s' instead.",
 
     NotTokenized: "The input source code has not been tokenized yet.",
-    Expected1Export: "Expected 1 export statement but got %d instead.",
+    Expected1Export: "Expected at least 1 export statement but got %d instead.",
     ExpectedStructs: "Expected at least 1 struct definition but got none instead.",
     Expected1Field: "Expected at least 1 field in whole file but got none instead.",
     ExportStructCantBeReferenced: "The struct '%s' which is referenced for export cannot be referenced inside the file by another struct.\nBut struct '%s' which was referenced for export was referenced by '%s'.",
     UnknownType: "The type '%s' used in struct '%s' is not recognised. Check manual.",
     //TwoStructsCantCrossReference: "Two structs can not reference each other in any way. But there was a cross reference with following path",
-    AStructCantReferenceItself: "A struct cannot reference itself directly or indirectly. But struct '%s' referenced itself in given fields '%s'.",
-    DidntFoundAStructToExport: "Did not find any struct to export in the source file.",
+    AStructCantReferenceItself: "A struct can only reference itself through optional fields, arrays or maps. But struct '%s' referenced itself in given fields '%s'.",
+    DidntFoundAStructToExport: "Did not find any struct or union named '%s' to export in the source file.",
     ExpectedNameForExport: "Expected a name for export statement at '%s' but got none instead.",
     UnexpectedTokenAfterStruct: "Got unexpected token '%s' after struct definition end at '%s'.",
     AStructMustHaveAtleast1Field: "A struct must have at least 1 field defined inside it. But struct '%s' has no fields defined.",
-    AnotherStructWithSameNameExists: "Another struct with same name '%s' already exists. Struct names must be unique.",
+    AnotherStructWithSameNameExists: "Another struct with same name '%s' already exists at '%s'. Declared again at '%s'. Struct names must be unique.",
     StructShouldStartWithCurlyBrace: "A struct definition should start with a curly brace '{' but at '%s' got '%s'.",
     ExpectedNameForStruct: "Expected a name for struct definition at '%s' but it was missing or either was not in preferred format.",
     ExpectedFieldAfterAnotherField: "Expected a field definition after field '%s' at '%s' in struct '%s' since struct was not closed yet but got none instead. Either you had a typo ",
     AnotherFieldWithSameNameExists: "Another field in struct '%s' with same name '%s' already exists in. Field names must be unique inside a struct.",
     ExpectedNameForField: "Expected a name for field definition at '%s' but it was missing or either was not in preferred format.",
     BracketNotClosed: "A opened bracket at '%s' was not closed for defining slice type. Got '%s' at '%s' instead of closing bracket ']'.",
-    ExpectedMapDefinition: "Expected a map keyword at '%s' because there was '{type} before it.",
+    ExpectedMapDefinition: "Expected a map keyword ('map', 'smap', 'vmap' or 'set') at '%s' because there was '{type} before it.",
     CurlyBraceNotClosed: "A opened curly brace at '%s' was not closed for defining struct or map. Consider checking.",
     NoTypeSpecifiedForMap: "No type was specified after '{' at '%s'. If a type for a field starts with curly brace it is considered as a map type.",
     ExpectedAValidType: "Expected a valid type at '%s' but got '%s' instead. Consider checking manual for valid types.",
     InvalidStructNaming: "The struct defined at '%s' with name '%s' can not have that name since that name is a default type.",
     UnexpectedTokenAfterField: "Got unexpected token '%s' after field definition at '%s'.",
-    CyclicReference: "Cyclic reference detected, path is: '%s'.",
+    CyclicReference: "Cyclic reference detected, path is: '%s'. Make one of the fields optional, an array or a map so it can end.",
+    ExpectedNameForEnum: "Expected a name for enum definition at '%s' but it was missing or either was not in preferred format.",
+    EnumShouldStartWithCurlyBrace: "An enum definition should start with a curly brace '{' but at '%s' got '%s'.",
+    InvalidEnumNaming: "The enum defined at '%s' with name '%s' can not have that name since that name is a default type.",
+    AnotherDeclarationWithSameNameExists: "Another struct, enum, union or type alias with same name '%s' already exists at '%s'. Declared again at '%s'.",
+    AnEnumMustHaveAtleast1Member: "An enum must have at least 1 member defined inside it. But enum '%s' has no members defined.",
+    AnotherEnumMemberWithSameNameExists: "Another member in enum '%s' with same name '%s' already exists. Member names must be unique inside an enum.",
+    TooManyEnumMembers: "The enum '%s' has %d members but an enum can have at most %d members.",
+    UnexpectedTokenInEnum: "Got unexpected token '%s' at '%s' inside enum '%s'. Expected a member name or '}'.",
+    UnexpectedTokenAfterEnum: "Got unexpected token '%s' after enum definition end at '%s'.",
+    ExpectedNameForUnion: "Expected a name for union definition at '%s' but it was missing or either was not in preferred format.",
+    UnionShouldStartWithCurlyBrace: "A union definition should start with a curly brace '{' but at '%s' got '%s'.",
+    InvalidUnionNaming: "The union defined at '%s' with name '%s' can not have that name since that name is a default type.",
+    AUnionMustHaveAtleast1Arm: "A union must have at least 1 arm defined inside it. But union '%s' has no arms defined.",
+    AnotherUnionArmWithSameNameExists: "Another arm in union '%s' with same name '%s' already exists. Arm names must be unique inside a union.",
+    TooManyUnionArms: "The union '%s' has %d arms but a union can have at most %d arms.",
+    UnexpectedTokenInUnion: "Got unexpected token '%s' at '%s' inside union '%s'. Expected an arm name or '}'.",
+    UnexpectedTokenAfterUnion: "Got unexpected token '%s' after union definition end at '%s'.",
+    UnionArmCantBeOptional: "The arm '%s' of union '%s' can not be optional since only one arm is sent at a time anyway.",
+    ExportedMoreThanOnce: "The struct '%s' was exported more than once at '%s'.",
+    ExpectedPathForImport: "Expected a quoted path for import statement at '%s' but got '%s' instead.",
+    ImportCycle: "Import cycle detected, path is: '%s'.",
+    ElementCantBeOptional: "Elements of arrays and maps can not be optional, got an optional element at '%s'.",
+    InvalidMapKeyType: "Map key type '%s' at '%s' must be a default non container type or an enum.",
+    ExpectedNameForConstant: "Expected a name for constant defined at '%s'.",
+    ExpectedValueForConstant: "Expected '=' followed by a non negative integer for constant '%s' at '%s'.",
+    AnotherConstantWithSameNameExists: "Another constant with same name '%s' already exists at '%s'. Declared again at '%s'.",
+    UnexpectedTokenAfterConstant: "Got unexpected token '%s' after constant definition end at '%s'.",
+    UnknownConstant: "Array size '%s' at '%s' is neither an integer, a declared constant nor 'vu32'.",
+    ExpectedRange: "Expected a range like '0..10' at '%s' but got '%s' instead.",
+    InvalidRange: "Invalid range '%d..%d' at '%s', minimum can not be greater than maximum.",
+    RangeOutOfBounds: "Range '%d..%d' at '%s' does not fit in type '%s'.",
+    RangeOnlyForIntegers: "Ranges can only be used with integer types but got type '%s' at '%s'.",
+    RangeRequired: "Type 'int' at '%s' requires a range like 'int 0..10'.",
+    InvalidFieldId: "Field id '%s' at '%s' must be an integer greater than 0.",
+    FieldIdsMustBeSetForAllFields: "Struct '%s' gives ids to some of its fields but field '%s' has none. Either all fields or none of them must have ids.",
+    AnotherFieldWithSameIdExists: "Fields '%s' and '%s' of struct '%s' have the same id %d. Field ids must be unique inside a struct.",
+    FieldIdsMustIncrease: "Field '%s' of struct '%s' has id %d which is not greater than id %d of field before it. Field ids must increase in declaration order.",
+    PackedStructCantHaveFieldIds: "Packed struct '%s' can not have field ids since packed structs are not evolvable.",
+    ExpectedReservedValue: "Expected field ids or quoted field names separated by commas after reserved at '%s' but got '%s' instead.",
+    FieldUsesReservedId: "Field '%s' of struct '%s' uses id %d which is reserved.",
+    FieldUsesReservedName: "Field '%s' of struct '%s' uses a reserved name.",
+    ExpectedNameForTypeAlias: "Expected a name for type alias defined at '%s'.",
+    InvalidTypeAliasNaming: "The type alias defined at '%s' with name '%s' can not have that name since that name is a default type.",
+    ExpectedEqualsForTypeAlias: "Expected '=' after type alias name '%s' at '%s' but got '%s' instead.",
+    UnexpectedTokenAfterTypeAlias: "Got unexpected token '%s' after type alias definition end at '%s'.",
+    ExpectedDefaultValue: "Expected a default value after '=' for field '%s' at '%s'.",
+    InvalidDefaultValue: "Default value '%s' of field '%s' at '%s' does not fit its type '%s'.",
+    ExpectedRobloxEnumFamily: "Expected a Roblox enum name like 'enum<Material>' at '%s'.",
+    UnknownRobloxEnum: "'%s' at '%s' is not a Roblox enum.",
+    ExpectedInstanceClass: "Expected a class name like 'instance<Part>' at '%s'.",
+    ExpectedBaseForStruct: "Expected a struct name after extends for struct '%s' defined at '%s'.",
+    BaseMustBeAStruct: "Struct '%s' extends '%s' which is not a struct.",
+    ExtensionCycle: "Struct '%s' extends itself, path is: '%s'.",
+    InheritedFieldCollision: "Field '%s' of struct '%s' has the same name as a field it inherits from struct '%s'.",
+    ExpectedTypeParameter: "Expected a type parameter name for generic struct '%s' at '%s' but got '%s' instead.",
+    AnotherTypeParameterWithSameNameExists: "Generic struct '%s' at '%s' has more than one type parameter named '%s'.",
+    TypeArgumentCountMismatch: "Generic struct '%s' takes %d type arguments but got %d at '%s'.",
+    NotAGenericStruct: "'%s' at '%s' is not a generic struct, it can't take type arguments.",
+    UnboundTypeParameter: "Type '%s' at '%s' is neither a declared type nor a type parameter of generic struct '%s'.",
+    GenericStructCantBeExported: "Generic struct '%s' can't be exported, export a struct with a field of an instance of it instead.",
+    GenericInstanceNameCollision: "Instance '%s' of generic struct '%s' has the same name as struct defined at '%s'.",
+    GenericInstantiationTooDeep: "Instances of generic struct '%s' nest deeper than %d levels, path is: '%s'.",
+    InvalidSetElementType: "Set element type '%s' at '%s' must be a default non container type or an enum since elements are keys of the set.",
+    DefaultNotSupportedForType: "Field '%s' at '%s' can not have a default value, only numbers, booleans, strings and enums can have one but its type is '%s'.",
+    ExpectedStructAfterPacked: "Expected a struct after packed modifier at '%s' but got '%s' instead.",
+    ExpectedQuantization: "Type '%s' at '%s' requires parameters like '%s(-512, 512, 0.01)'.",
+    InvalidQuantization: "Invalid quantization '%s(%g, %g, %g)' at '%s', minimum must be less than maximum, precision must be positive and steps must fit in 32 bits.",
+    InvalidArraySize: "Array size '%s' at '%s' is %d but fixed arrays must have at least 1 element.",
+    UnknownAliasTarget: "The type '%s' used by type alias '%s' at '%s' is not recognised. Check manual.",
 }
 
 // Public Constants
@@ -93,4 +162,73 @@ const (
     InvalidStructNaming
     UnexpectedTokenAfterField
     CyclicReference
+    ExpectedNameForEnum
+    EnumShouldStartWithCurlyBrace
+    InvalidEnumNaming
+    AnotherDeclarationWithSameNameExists
+    AnEnumMustHaveAtleast1Member
+    AnotherEnumMemberWithSameNameExists
+    TooManyEnumMembers
+    UnexpectedTokenInEnum
+    UnexpectedTokenAfterEnum
+    ExpectedNameForUnion
+    UnionShouldStartWithCurlyBrace
+    InvalidUnionNaming
+    AUnionMustHaveAtleast1Arm
+    AnotherUnionArmWithSameNameExists
+    TooManyUnionArms
+    UnexpectedTokenInUnion
+    UnexpectedTokenAfterUnion
+    UnionArmCantBeOptional
+    ExportedMoreThanOnce
+    ExpectedPathForImport
+    ImportCycle
+    ElementCantBeOptional
+    InvalidMapKeyType
+    ExpectedNameForConstant
+    ExpectedValueForConstant
+    AnotherConstantWithSameNameExists
+    UnexpectedTokenAfterConstant
+    UnknownConstant
+    ExpectedRange
+    InvalidRange
+    RangeOutOfBounds
+    RangeOnlyForIntegers
+    RangeRequired
+    ExpectedQuantization
+    InvalidQuantization
+    ExpectedStructAfterPacked
+    InvalidFieldId
+    FieldIdsMustBeSetForAllFields
+    AnotherFieldWithSameIdExists
+    FieldIdsMustIncrease
+    PackedStructCantHaveFieldIds
+    ExpectedReservedValue
+    FieldUsesReservedId
+    FieldUsesReservedName
+    ExpectedNameForTypeAlias
+    InvalidTypeAliasNaming
+    ExpectedEqualsForTypeAlias
+    UnexpectedTokenAfterTypeAlias
+    ExpectedDefaultValue
+    InvalidDefaultValue
+    DefaultNotSupportedForType
+    ExpectedRobloxEnumFamily
+    UnknownRobloxEnum
+    ExpectedInstanceClass
+    ExpectedBaseForStruct
+    BaseMustBeAStruct
+    ExtensionCycle
+    InheritedFieldCollision
+    ExpectedTypeParameter
+    AnotherTypeParameterWithSameNameExists
+    TypeArgumentCountMismatch
+    NotAGenericStruct
+    UnboundTypeParameter
+    GenericStructCantBeExported
+    GenericInstanceNameCollision
+    GenericInstantiationTooDeep
+    InvalidSetElementType
+    InvalidArraySize
+    UnknownAliasTarget
 )
@@ -199,7 +199,7 @@ func CreateAndWriteFile(dir string, name string, content string) *errors.StackEr
         }
     }
 
-    file, err := os.OpenFile(path, os.O_CREATE, 0644)
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
     if err != nil {
         if os.IsExist(err) {
             ui.Log(config.GRANDMASTER, "warning", fmt.Sprintf("File '%s' already exists. Deleting...", path))